@@ -0,0 +1,203 @@
+package main
+
+import (
+	"bufio"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+
+	"github.com/xen0tic/utils/devices/concox"
+	"github.com/xen0tic/utils/generics"
+)
+
+const (
+	formatAuto   = "auto"
+	formatHex    = "hex"
+	formatBinary = "binary"
+
+	maxLineLength = 1 << 20
+)
+
+// hexRun matches the hex frames in a log line. Twenty digits is the shortest packet, which keeps
+// timestamps and IMEIs out.
+var hexRun = regexp.MustCompile(`[0-9a-fA-F]{20,}`)
+
+// record is one line of output.
+type record struct {
+	Source       string      `json:"source"`
+	Line         int         `json:"line,omitempty"`
+	Imei         string      `json:"imei,omitempty"`
+	Protocol     string      `json:"protocol,omitempty"`
+	ProtocolName string      `json:"protocolName,omitempty"`
+	Raw          string      `json:"raw"`
+	Result       interface{} `json:"result,omitempty"`
+	Error        string      `json:"error,omitempty"`
+}
+
+// decoder frames, decodes and filters the packets of each input and writes them as JSON lines.
+type decoder struct {
+	out       *json.Encoder
+	imei      generics.Imei
+	protocols map[byte]bool
+	discarded bool
+}
+
+// session is the state of one input: the IMEI of the last login applies to the packets that follow it.
+// A binary capture is framed as one stream, a text input line by line, as each log line holds whole
+// frames and a stray hex run must not hold back the lines after it.
+type session struct {
+	source string
+	line   int
+	imei   generics.Imei
+	framer *concox.Framer
+}
+
+func newDecoder(w io.Writer) *decoder {
+	return &decoder{out: json.NewEncoder(w)}
+}
+
+func (d *decoder) run(source string, r io.Reader, format string) error {
+	s := &session{source: source, framer: concox.NewFramer()}
+	reader := bufio.NewReader(r)
+
+	if format == formatAuto {
+		format = formatHex
+		if start, err := reader.Peek(2); err == nil && (concox.IsNormalPackage(start) || concox.IsLongPackage(start)) {
+			format = formatBinary
+		}
+	}
+
+	if format == formatBinary {
+		return d.binary(s, reader)
+	}
+	return d.hex(s, reader)
+}
+
+func (d *decoder) binary(s *session, r io.Reader) error {
+	chunk := make([]byte, 4096)
+	for {
+		n, err := r.Read(chunk)
+		if n > 0 {
+			if err := d.feed(s, chunk[:n]); err != nil {
+				return err
+			}
+		}
+		if err == io.EOF {
+			return d.flush(s)
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", s.source, err)
+		}
+	}
+}
+
+func (d *decoder) hex(s *session, r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineLength)
+
+	for scanner.Scan() {
+		s.line++
+		line := scanner.Bytes()
+
+		if imei := logImei(line); imei != "" {
+			s.imei = imei
+		}
+
+		for _, run := range hexRun.FindAll(line, -1) {
+			data := make([]byte, len(run)/2)
+			if _, err := hex.Decode(data, run[:len(data)*2]); err != nil {
+				continue
+			}
+			if err := d.feed(s, data); err != nil {
+				return err
+			}
+		}
+		if err := d.flush(s); err != nil {
+			return err
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("%s: %w", s.source, err)
+	}
+	return nil
+}
+
+func (d *decoder) feed(s *session, data []byte) error {
+	frames, discards := s.framer.Feed(data)
+	return d.write(s, frames, discards)
+}
+
+// flush rescans the bytes left in the framer at the end of a line or a stream.
+func (d *decoder) flush(s *session) error {
+	frames, discards := s.framer.Flush()
+	return d.write(s, frames, discards)
+}
+
+func (d *decoder) write(s *session, frames [][]byte, discards []concox.Discard) error {
+	if d.discarded && (d.imei == "" || s.imei == d.imei) {
+		for _, discard := range discards {
+			err := d.out.Encode(record{
+				Source: s.source,
+				Line:   s.line,
+				Imei:   s.imei.String(),
+				Error:  fmt.Sprintf("discarded %d bytes: %s", discard.Bytes, discard.Reason),
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, frame := range frames {
+		protocol, _ := concox.PackageType(frame)
+		result, err := concox.Decode(frame)
+		if login, ok := result.(concox.Login); ok && err == nil {
+			s.imei = login.Imei
+		}
+
+		if d.imei != "" && s.imei != d.imei {
+			continue
+		}
+		if d.protocols != nil && !d.protocols[protocol] {
+			continue
+		}
+
+		r := record{
+			Source:       s.source,
+			Line:         s.line,
+			Imei:         s.imei.String(),
+			Protocol:     fmt.Sprintf("0x%02x", protocol),
+			ProtocolName: concox.ProtocolString(protocol),
+			Raw:          hex.EncodeToString(frame),
+			Result:       result,
+		}
+		if err != nil {
+			r.Error = err.Error()
+		}
+		if err := d.out.Encode(r); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// logImei returns the imei field of a JSON log line, if it is 15 digits. The check digit is not verified,
+// as devices with a wrong one log in all the same.
+func logImei(line []byte) generics.Imei {
+	if len(line) == 0 || line[0] != '{' {
+		return ""
+	}
+
+	var entry struct {
+		Imei string `json:"imei"`
+	}
+	if json.Unmarshal(line, &entry) != nil {
+		return ""
+	}
+	imei, _ := generics.ParseIMEIDigits(entry.Imei)
+	return imei
+}
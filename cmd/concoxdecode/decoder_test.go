@@ -0,0 +1,138 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xen0tic/utils/devices/concox"
+)
+
+func packet(t *testing.T, protocol byte, content string, sn uint16) []byte {
+	t.Helper()
+
+	body, err := hex.DecodeString(content)
+	require.NoError(t, err)
+	return concox.EncodePackage(false, protocol, body, sn)
+}
+
+func decodeRecords(t *testing.T, d *decoder, out *bytes.Buffer, input string, format string) []record {
+	t.Helper()
+
+	require.NoError(t, d.run("test", strings.NewReader(input), format))
+
+	var records []record
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var r record
+		require.NoError(t, json.Unmarshal([]byte(line), &r))
+		records = append(records, r)
+	}
+	return records
+}
+
+func TestDecoderHex(t *testing.T) {
+	login := packet(t, concox.ParserLogin, "0868120145233604", 1)
+	location := packet(t, concox.ParserLocationGT06, "0b081d112e10cc027ac7eb0c46584900148f01cc00287d001fb8", 2)
+	status := packet(t, concox.ParserStatus, "4405040002", 3)
+
+	input := `{"level":"info","timestamp":"Mon, 2023-03-06 10:00:00 UTC","msg":"received","data":"` + hex.EncodeToString(login) + `"}` + "\n" +
+		hex.EncodeToString(location) + " " + hex.EncodeToString(status) + "\n" +
+		"no frames here\n"
+
+	var out bytes.Buffer
+	records := decodeRecords(t, newDecoder(&out), &out, input, formatAuto)
+	require.Len(t, records, 3)
+	assert.Equal(t, "0x01", records[0].Protocol)
+	assert.Equal(t, 1, records[0].Line)
+	assert.Equal(t, "868120145233604", records[1].Imei)
+	assert.Equal(t, "Location", records[1].ProtocolName)
+	assert.Equal(t, hex.EncodeToString(status), records[2].Raw)
+	assert.Empty(t, records[2].Error)
+
+	out.Reset()
+	d := newDecoder(&out)
+	d.protocols, _ = parseProtocols("0x12, 13")
+	records = decodeRecords(t, d, &out, input, formatHex)
+	require.Len(t, records, 2)
+	assert.Equal(t, "0x12", records[0].Protocol)
+	assert.Equal(t, "0x13", records[1].Protocol)
+
+	out.Reset()
+	d = newDecoder(&out)
+	d.imei = "356307042441013"
+	assert.Empty(t, decodeRecords(t, d, &out, input, formatHex))
+}
+
+func TestDecoderBinary(t *testing.T) {
+	login := packet(t, concox.ParserLogin, "0868120145233604", 1)
+	status := packet(t, concox.ParserStatus, "4405040002", 2)
+	input := string(login) + "garbage" + string(status)
+
+	var out bytes.Buffer
+	d := newDecoder(&out)
+	d.discarded = true
+	records := decodeRecords(t, d, &out, input, formatAuto)
+	require.Len(t, records, 3)
+	// Discards are reported before the packets of the same read.
+	assert.Contains(t, records[0].Error, "discarded 7 bytes")
+	assert.Equal(t, "0x01", records[1].Protocol)
+	assert.Equal(t, "868120145233604", records[2].Imei)
+}
+
+func TestParseProtocols(t *testing.T) {
+	filter, err := parseProtocols("12,0x22,94")
+	require.NoError(t, err)
+	assert.Equal(t, map[byte]bool{0x12: true, 0x22: true, 0x94: true}, filter)
+
+	_, err = parseProtocols("12,zz")
+	assert.Error(t, err)
+}
+
+func TestDecoderStrayHexRun(t *testing.T) {
+	login := hex.EncodeToString(packet(t, concox.ParserLogin, "0868120145233604", 1))
+
+	input := `{"msg":"request","req":"00000000000000007979ffff00000000"}` + "\n" +
+		`{"msg":"request","req":"7878f000000000000000000000"}` + "\n"
+	for i := 0; i < 5; i++ {
+		input += `{"msg":"received","data":"` + login + `"}` + "\n"
+	}
+
+	var out bytes.Buffer
+	records := decodeRecords(t, newDecoder(&out), &out, input, formatHex)
+	require.Len(t, records, 5)
+	assert.Equal(t, 3, records[0].Line)
+
+	out.Reset()
+	d := newDecoder(&out)
+	d.discarded = true
+	d.imei = "868120145233604"
+	records = decodeRecords(t, d, &out, input+"7878f0000000000000000000\n", formatHex)
+	require.Len(t, records, 7)
+	assert.Equal(t, "discarded 2 bytes: concox: packet is too short", records[5].Error)
+	assert.Equal(t, 8, records[5].Line)
+}
+
+func TestDecoderBadCheckDigit(t *testing.T) {
+	login := packet(t, concox.ParserLogin, "0868120145233605", 1)
+	status := packet(t, concox.ParserStatus, "4405040002", 2)
+	input := hex.EncodeToString(login) + "\n" +
+		`{"imei":"868120145233605","data":"` + hex.EncodeToString(status) + `"}` + "\n"
+
+	var out bytes.Buffer
+	d := newDecoder(&out)
+	d.imei = "868120145233605"
+	records := decodeRecords(t, d, &out, input, formatHex)
+	require.Len(t, records, 2)
+	assert.Equal(t, "0x01", records[0].Protocol)
+	assert.Equal(t, "868120145233605", records[1].Imei)
+
+	assert.Equal(t, "868120145233605", logImei([]byte(`{"imei":"868120145233605"}`)).String())
+	assert.Empty(t, logImei([]byte(`{"imei":"86812014523360"}`)))
+}
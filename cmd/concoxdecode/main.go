@@ -0,0 +1,91 @@
+// Command concoxdecode decodes Concox traffic captured offline and prints one JSON object per packet.
+//
+// It reads the named files, or stdin when there are none. Input is either a binary capture of the raw
+// TCP stream or text with one or more hex frames per line, such as the JSON lines of the gateway log.
+//
+//	concoxdecode -imei 868120145233604 -protocol 12,22 log/gateway.log
+//	xxd -p capture.bin | concoxdecode -format hex
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/xen0tic/utils/generics"
+)
+
+func main() {
+	var (
+		format    = flag.String("format", formatAuto, "input format: auto, hex or binary")
+		imei      = flag.String("imei", "", "only print packets of this IMEI")
+		protocols = flag.String("protocol", "", "only print these comma separated protocol numbers, in hex")
+		discarded = flag.Bool("discarded", false, "also print the bytes that could not be framed; -imei applies to them, -protocol does not")
+	)
+	flag.Usage = func() {
+		_, _ = fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [flags] [file ...]\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	d := newDecoder(os.Stdout)
+	d.discarded = *discarded
+
+	if *imei != "" {
+		value, err := generics.ParseIMEIDigits(*imei)
+		if err != nil {
+			fail(fmt.Errorf("-imei: %w", err))
+		}
+		d.imei = value
+	}
+	if *protocols != "" {
+		filter, err := parseProtocols(*protocols)
+		if err != nil {
+			fail(fmt.Errorf("-protocol: %w", err))
+		}
+		d.protocols = filter
+	}
+	if *format != formatAuto && *format != formatHex && *format != formatBinary {
+		fail(fmt.Errorf("-format: unknown format %q", *format))
+	}
+
+	if flag.NArg() == 0 {
+		if err := d.run("stdin", os.Stdin, *format); err != nil {
+			fail(err)
+		}
+		return
+	}
+
+	for _, name := range flag.Args() {
+		file, err := os.Open(name)
+		if err != nil {
+			fail(err)
+		}
+		err = d.run(name, file, *format)
+		_ = file.Close()
+		if err != nil {
+			fail(err)
+		}
+	}
+}
+
+// parseProtocols parses a comma separated list of hex protocol numbers, with or without a 0x prefix.
+func parseProtocols(value string) (map[byte]bool, error) {
+	filter := make(map[byte]bool)
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(item)), "0x")
+		protocol, err := strconv.ParseUint(item, 16, 8)
+		if err != nil {
+			return nil, fmt.Errorf("invalid protocol number %q", item)
+		}
+		filter[byte(protocol)] = true
+	}
+	return filter, nil
+}
+
+func fail(err error) {
+	_, _ = fmt.Fprintln(os.Stderr, "concoxdecode:", err)
+	os.Exit(1)
+}
@@ -0,0 +1,52 @@
+package devices
+
+import (
+	"github.com/xen0tic/utils/devices/concox"
+	"github.com/xen0tic/utils/generics"
+	"golang.org/x/exp/slices"
+)
+
+// concoxProtocol serves GT06 and X3 devices, which share framing and decoders. The GT06 protocol does not
+// claim packets only X3 devices send, so Detect can tell the families apart on those.
+type concoxProtocol struct {
+	x3 bool
+}
+
+var x3Protocols = []byte{
+	concox.ParserLocationX3,
+	concox.ParserAlarmX3,
+	concox.ParserAlarmX3V2,
+	concox.ParserLBSLocationX3,
+	concox.ParserWifiInformation,
+	concox.ParserOnlineCommandLongResponse,
+}
+
+func init() {
+	Register(generics.DEVICE_TYPE_GT06, concoxProtocol{})
+	Register(generics.DEVICE_TYPE_X3, concoxProtocol{x3: true})
+}
+
+func (p concoxProtocol) Detect(data []byte) bool {
+	if len(data) < 2 || !concox.IsPacketFromDevice(data) {
+		return false
+	}
+	return p.x3 || !slices.Contains(x3Protocols, concox.GetPackageType(data))
+}
+
+func (concoxProtocol) Frame(data []byte) ([][]byte, []byte) {
+	framer := concox.NewFramer()
+	frames, _ := framer.Feed(data)
+	return frames, framer.Bytes()
+}
+
+func (concoxProtocol) Decode(packet []byte) (interface{}, error) {
+	return concox.Decode(packet)
+}
+
+func (concoxProtocol) BuildAck(packet []byte) ([]byte, error) {
+	return concox.BuildResponse(packet)
+}
+
+func (concoxProtocol) EncodeCommand(command string, serial uint16) ([]byte, error) {
+	return concox.EncodeOnlineCommand(concox.OnlineCommand{SerialNumber: serial, Content: command})
+}
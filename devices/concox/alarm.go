@@ -0,0 +1,166 @@
+package concox
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/xen0tic/utils/generics"
+)
+
+const (
+	AlarmNormal                 = 0x00
+	AlarmSOS                    = 0x01
+	AlarmPowerCut               = 0x02
+	AlarmVibration              = 0x03
+	AlarmEnterFence             = 0x04
+	AlarmExitFence              = 0x05
+	AlarmOverSpeed              = 0x06
+	AlarmMoving                 = 0x09
+	AlarmEnterGpsDeadZone       = 0x0a
+	AlarmExitGpsDeadZone        = 0x0b
+	AlarmPowerOn                = 0x0c
+	AlarmGpsFirstFix            = 0x0d
+	AlarmExternalLowBattery     = 0x0e
+	AlarmExternalLowBatteryProt = 0x0f
+	AlarmSimChange              = 0x10
+	AlarmPowerOff               = 0x11
+	AlarmAirplaneMode           = 0x12
+	AlarmTamper                 = 0x13
+	AlarmDoor                   = 0x14
+	AlarmLowPowerShutdown       = 0x15
+	AlarmSound                  = 0x16
+	AlarmPseudoBaseStation      = 0x17
+	AlarmCoverOpen              = 0x18
+	AlarmInternalLowBattery     = 0x19
+	AlarmEnterDeepSleep         = 0x20
+	AlarmFall                   = 0x23
+	AlarmHarshAcceleration      = 0x29
+	AlarmSharpLeftCornering     = 0x2a
+	AlarmSharpRightCornering    = 0x2b
+	AlarmCollision              = 0x2c
+	AlarmHarshBraking           = 0x30
+	AlarmDeviceUnplugged        = 0x32
+	AlarmAccOn                  = 0xfe
+	AlarmAccOff                 = 0xff
+)
+
+// alarmHeaderLength covers the date-time, the GPS block and the LBS length byte.
+const alarmHeaderLength = dateTimeLength + gpsBlockLength + 1
+
+var alarmModes = map[byte]string{
+	AlarmNormal:                 "Normal",
+	AlarmSOS:                    "SOS",
+	AlarmPowerCut:               "Power Cut",
+	AlarmVibration:              "Vibration",
+	AlarmEnterFence:             "Enter Fence",
+	AlarmExitFence:              "Exit Fence",
+	AlarmOverSpeed:              "Over Speed",
+	AlarmMoving:                 "Moving",
+	AlarmEnterGpsDeadZone:       "Enter GPS Dead Zone",
+	AlarmExitGpsDeadZone:        "Exit GPS Dead Zone",
+	AlarmPowerOn:                "Power On",
+	AlarmGpsFirstFix:            "GPS First Fix",
+	AlarmExternalLowBattery:     "External Low Battery",
+	AlarmExternalLowBatteryProt: "External Low Battery Protection",
+	AlarmSimChange:              "SIM Change",
+	AlarmPowerOff:               "Power Off",
+	AlarmAirplaneMode:           "Airplane Mode",
+	AlarmTamper:                 "Tamper",
+	AlarmDoor:                   "Door",
+	AlarmLowPowerShutdown:       "Low Power Shutdown",
+	AlarmSound:                  "Sound",
+	AlarmPseudoBaseStation:      "Pseudo Base Station",
+	AlarmCoverOpen:              "Cover Open",
+	AlarmInternalLowBattery:     "Internal Low Battery",
+	AlarmEnterDeepSleep:         "Enter Deep Sleep",
+	AlarmFall:                   "Fall",
+	AlarmHarshAcceleration:      "Harsh Acceleration",
+	AlarmSharpLeftCornering:     "Sharp Left Cornering",
+	AlarmSharpRightCornering:    "Sharp Right Cornering",
+	AlarmCollision:              "Collision",
+	AlarmHarshBraking:           "Harsh Braking",
+	AlarmDeviceUnplugged:        "Device Unplugged",
+	AlarmAccOn:                  "ACC On",
+	AlarmAccOff:                 "ACC Off",
+}
+
+// AlarmModeString returns the name of an alarm type.
+func AlarmModeString(mode byte) string {
+	if name, ok := alarmModes[mode]; ok {
+		return name
+	}
+	return "Unknown"
+}
+
+// LanguageString returns the name of a language byte.
+func LanguageString(language byte) string {
+	switch language {
+	case LanguageChinese:
+		return "Chinese"
+	case LanguageEnglish:
+		return "English"
+	}
+	return "Unknown"
+}
+
+// DecodeAlarm decodes a GT06 (0x16) or X3 (0x26, 0x27) alarm packet. FenceNumber is only set for 0x27.
+func DecodeAlarm(input []byte) (generics.Alarm, error) {
+	var alarm generics.Alarm
+
+	protocol, content, err := packetContent(input, alarmHeaderLength, ParserAlarmGT06, ParserAlarmX3, ParserAlarmX3V2)
+	if err != nil {
+		return alarm, err
+	}
+
+	var location generics.Location
+	location.Timestamp = decodeDateTime(content)
+	decodeGpsBlock(content[dateTimeLength:], &location)
+
+	// The LBS length byte counts itself, a zero length means the cell block is absent. The cell is read
+	// within that length only, so a length too short for it is an error rather than a cell made of the
+	// status bytes.
+	offset := dateTimeLength + gpsBlockLength
+	if lbsLength := int(content[offset]); lbsLength > 0 {
+		if len(content) < offset+lbsLength {
+			return alarm, ErrShortPacket
+		}
+		if _, ok := decodeCell(content[offset+1:offset+lbsLength], &location); !ok {
+			return alarm, ErrShortPacket
+		}
+		offset += lbsLength
+	} else {
+		offset++
+	}
+
+	if len(content) < offset+5 {
+		return alarm, ErrShortPacket
+	}
+	status := content[offset:]
+
+	alarm.Latitude = location.Lat
+	alarm.Longitude = location.Lng
+	alarm.Speed = location.Speed
+	alarm.GpsInformation = location.Gps
+	alarm.Course = location.Course
+	alarm.Mcc = strconv.Itoa(int(location.Mcc))
+	alarm.Mnc = strconv.Itoa(int(location.Mnc))
+	alarm.Lac = strconv.Itoa(int(location.Lac))
+	alarm.CellID = strconv.FormatInt(location.CellId, 10)
+	alarm.TerminalInformation = fmt.Sprintf("%02x", status[0])
+	alarm.VoltageLevel = strconv.Itoa(int(status[1]))
+	alarm.GsmSignal = strconv.Itoa(int(status[2]))
+	alarm.AlarmMode = AlarmModeString(status[3])
+	alarm.Language = LanguageString(status[4])
+	alarm.Date = location.Timestamp.Format(generics.DateFormat)
+	alarm.Timestamp = location.Timestamp
+	alarm.SerialNumber = fmt.Sprintf("%04x", GetPackageSn(input))
+
+	if protocol == ParserAlarmX3V2 {
+		if len(status) < 6 {
+			return alarm, ErrShortPacket
+		}
+		alarm.FenceNumber = int(status[5])
+	}
+
+	return alarm, nil
+}
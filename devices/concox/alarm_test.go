@@ -0,0 +1,46 @@
+package concox
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeAlarm(t *testing.T) {
+	packet := buildPacket(t, ParserAlarmGT06, "0b081d112e10cc027ac7eb0c46584900148f0901cc00287d001fb8440403010203", 0x0a)
+	require.True(t, ValidatePackage(packet))
+
+	alarm, err := DecodeAlarm(packet)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2011, 8, 29, 17, 46, 16, 0, time.UTC), alarm.Timestamp)
+	assert.Equal(t, "23.111668", alarm.Latitude)
+	assert.Equal(t, "114.409285", alarm.Longitude)
+	assert.Equal(t, "460", alarm.Mcc)
+	assert.Equal(t, "0", alarm.Mnc)
+	assert.Equal(t, "10365", alarm.Lac)
+	assert.Equal(t, "8120", alarm.CellID)
+	assert.Equal(t, "44", alarm.TerminalInformation)
+	assert.Equal(t, "4", alarm.VoltageLevel)
+	assert.Equal(t, "3", alarm.GsmSignal)
+	assert.Equal(t, "SOS", alarm.AlarmMode)
+	assert.Equal(t, "English", alarm.Language)
+	assert.Equal(t, "000a", alarm.SerialNumber)
+	assert.Zero(t, alarm.FenceNumber)
+
+	fence := buildPacket(t, ParserAlarmX3V2, "0b081d112e10cc027ac7eb0c46584900148f0901cc00287d001fb844040305020a", 0x0b)
+	require.True(t, ValidatePackage(fence))
+	alarm, err = DecodeAlarm(fence)
+	require.NoError(t, err)
+	assert.Equal(t, "Exit Fence", alarm.AlarmMode)
+	assert.Equal(t, 10, alarm.FenceNumber)
+
+	_, err = DecodeAlarm(packet[:len(packet)-8])
+	assert.Error(t, err)
+
+	// An LBS length of 4 leaves 3 bytes for an 8 byte cell.
+	shortCell := buildPacket(t, ParserAlarmGT06, "0b081d112e10cc027ac7eb0c46584900148f0401cc00440403010203", 0x0c)
+	_, err = DecodeAlarm(shortCell)
+	assert.ErrorIs(t, err, ErrShortPacket)
+}
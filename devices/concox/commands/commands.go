@@ -0,0 +1,132 @@
+// Package commands builds validated text commands for Concox devices.
+//
+// Commands are rendered with concox.EncodeOnlineCommand rather than concox.CreatePackageForDevice: the packet
+// then carries the serial number of the device's own sequence, and a command too long to send is reported
+// instead of rendered as nil.
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/xen0tic/utils/devices/concox"
+)
+
+const (
+	MinTimerInterval = 5
+	MaxTimerInterval = 18000
+	MaxSosNumbers    = 3
+	maxPhoneLength   = 20
+)
+
+var (
+	// ErrInterval occurs when an upload interval is out of the range the firmware accepts.
+	ErrInterval = errors.New("commands: interval must be between 5 and 18000 seconds")
+	// ErrArgument occurs when a text argument is empty or contains ',' or '#', which delimit commands.
+	ErrArgument = errors.New("commands: argument is empty or contains ',' or '#'")
+	// ErrPhone occurs when a phone number has anything but digits and a leading '+'.
+	ErrPhone = errors.New("commands: invalid phone number")
+	// ErrPort occurs when a server port is out of range.
+	ErrPort = errors.New("commands: invalid port")
+	// ErrSosNumbers occurs when no number or more than three numbers are given.
+	ErrSosNumbers = errors.New("commands: between one and three SOS numbers are required")
+)
+
+// Command is the text of a device command, e.g. "RELAY,1#".
+type Command string
+
+func (c Command) String() string {
+	return string(c)
+}
+
+// Packet renders the command as an online command packet (0x80), numbered serial. It fails with
+// concox.ErrCommandTooLong when the command does not fit a packet.
+func (c Command) Packet(serial uint16) ([]byte, error) {
+	return concox.EncodeOnlineCommand(concox.OnlineCommand{SerialNumber: serial, Content: string(c)})
+}
+
+// Relay cuts (true) or restores (false) the oil and electricity relay.
+func Relay(cut bool) Command {
+	if cut {
+		return "RELAY,1#"
+	}
+	return "RELAY,0#"
+}
+
+// Timer sets the upload interval in seconds while ACC is on and while it is off.
+func Timer(accOn, accOff int) (Command, error) {
+	for _, interval := range []int{accOn, accOff} {
+		if interval < MinTimerInterval || interval > MaxTimerInterval {
+			return "", ErrInterval
+		}
+	}
+	return Command(fmt.Sprintf("TIMER,%d,%d#", accOn, accOff)), nil
+}
+
+// Apn sets the access point name, with user and password when the carrier needs them.
+func Apn(name, user, password string) (Command, error) {
+	if !validArgument(name) {
+		return "", ErrArgument
+	}
+	if user == "" && password == "" {
+		return Command(fmt.Sprintf("APN,%s#", name)), nil
+	}
+	if !validArgument(user) || !validArgument(password) {
+		return "", ErrArgument
+	}
+	return Command(fmt.Sprintf("APN,%s,%s,%s#", name, user, password)), nil
+}
+
+// Server points the device to host and port over TCP. The address mode is 0 for an IP address and 1
+// for a domain name.
+func Server(host string, port int) (Command, error) {
+	if !validArgument(host) {
+		return "", ErrArgument
+	}
+	if port <= 0 || port > 65535 {
+		return "", ErrPort
+	}
+
+	mode := 1
+	if net.ParseIP(host) != nil {
+		mode = 0
+	}
+	return Command(fmt.Sprintf("SERVER,%d,%s,%d,0#", mode, host, port)), nil
+}
+
+// Sos adds up to three SOS numbers.
+func Sos(numbers ...string) (Command, error) {
+	if len(numbers) == 0 || len(numbers) > MaxSosNumbers {
+		return "", ErrSosNumbers
+	}
+	for _, number := range numbers {
+		if !validPhone(number) {
+			return "", ErrPhone
+		}
+	}
+	return Command(fmt.Sprintf("SOS,A,%s#", strings.Join(numbers, ","))), nil
+}
+
+// Reset restarts the device.
+func Reset() Command {
+	return "RESET#"
+}
+
+// Factory restores the factory settings.
+func Factory() Command {
+	return "FACTORY#"
+}
+
+func validArgument(value string) bool {
+	return value != "" && !strings.ContainsAny(value, ",#")
+}
+
+func validPhone(number string) bool {
+	digits := strings.TrimPrefix(number, "+")
+	if digits == "" || len(digits) > maxPhoneLength {
+		return false
+	}
+	return strings.Trim(digits, "0123456789") == ""
+}
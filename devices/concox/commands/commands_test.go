@@ -0,0 +1,58 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xen0tic/utils/devices/concox"
+)
+
+func TestCommands(t *testing.T) {
+	assert.Equal(t, Command("RELAY,1#"), Relay(true))
+	assert.Equal(t, Command("RELAY,0#"), Relay(false))
+	assert.Equal(t, Command("RESET#"), Reset())
+	assert.Equal(t, Command("FACTORY#"), Factory())
+
+	command, err := Timer(10, 60)
+	require.NoError(t, err)
+	assert.Equal(t, Command("TIMER,10,60#"), command)
+	_, err = Timer(4, 60)
+	assert.ErrorIs(t, err, ErrInterval)
+
+	command, err = Apn("mtnirancell", "", "")
+	require.NoError(t, err)
+	assert.Equal(t, Command("APN,mtnirancell#"), command)
+	command, err = Apn("internet", "user", "pass")
+	require.NoError(t, err)
+	assert.Equal(t, Command("APN,internet,user,pass#"), command)
+	_, err = Apn("inter,net", "", "")
+	assert.ErrorIs(t, err, ErrArgument)
+
+	command, err = Server("gps.example.com", 7700)
+	require.NoError(t, err)
+	assert.Equal(t, Command("SERVER,1,gps.example.com,7700,0#"), command)
+	command, err = Server("10.0.0.1", 7700)
+	require.NoError(t, err)
+	assert.Equal(t, Command("SERVER,0,10.0.0.1,7700,0#"), command)
+	_, err = Server("10.0.0.1", 70000)
+	assert.ErrorIs(t, err, ErrPort)
+
+	command, err = Sos("+989121234567", "09121234567")
+	require.NoError(t, err)
+	assert.Equal(t, Command("SOS,A,+989121234567,09121234567#"), command)
+	_, err = Sos("0912-123")
+	assert.ErrorIs(t, err, ErrPhone)
+	_, err = Sos("1", "2", "3", "4")
+	assert.ErrorIs(t, err, ErrSosNumbers)
+
+	packet, err := Relay(true).Packet(5)
+	require.NoError(t, err)
+	assert.Equal(t, byte(concox.ParserOnlineCommandProtocol), concox.GetPackageType(packet))
+	assert.Equal(t, "RELAY,1#", string(packet[9:17]))
+	assert.Equal(t, uint16(5), concox.GetPackageSn(packet))
+
+	_, err = Command(strings.Repeat("A", concox.MaxOnlineCommandLength+1)).Packet(6)
+	assert.ErrorIs(t, err, concox.ErrCommandTooLong)
+}
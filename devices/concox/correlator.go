@@ -0,0 +1,203 @@
+package concox
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrCommandTimeout is the result of a command that got no response before its deadline.
+	ErrCommandTimeout = errors.New("concox: command response timed out")
+	// ErrUnknownServerFlag occurs when a response carries a server flag that is not pending.
+	ErrUnknownServerFlag = errors.New("concox: no pending command for server flag")
+	// ErrForeignServerFlag occurs when a response carries the server flag of a command sent to another
+	// device. The command stays pending.
+	ErrForeignServerFlag = errors.New("concox: server flag belongs to another device")
+)
+
+// CommandResponse is an online command response (0x15 or 0x21).
+type CommandResponse struct {
+	ServerFlag uint32
+	Content    string
+	Encoding   byte
+	Language   uint16
+}
+
+// DecodeCommandResponse decodes an online command response. 0x15 carries a command length byte and an
+// optional language, 0x21 carries an encoding byte instead. Content is always UTF-8: it is decoded from
+// UTF-16BE when the language is Chinese or the encoding is EncodingUTF16, and read as ASCII otherwise.
+func DecodeCommandResponse(input []byte) (CommandResponse, error) {
+	var response CommandResponse
+
+	protocol, content, err := packetContent(input, 0, ParserOnlineCommandResponse, ParserOnlineCommandLongResponse)
+	if err != nil {
+		return response, err
+	}
+
+	switch protocol {
+	case ParserOnlineCommandResponse:
+		if len(content) < 5 || int(content[0]) < 4 || len(content) < 1+int(content[0]) {
+			return response, ErrShortPacket
+		}
+		end := 1 + int(content[0])
+		response.ServerFlag = binary.BigEndian.Uint32(content[1:5])
+		if len(content) >= end+2 {
+			response.Language = binary.BigEndian.Uint16(content[end : end+2])
+		}
+		response.Content = decodeText(content[5:end], response.Language == LanguageChinese)
+	case ParserOnlineCommandLongResponse:
+		if len(content) < 5 {
+			return response, ErrShortPacket
+		}
+		response.ServerFlag = binary.BigEndian.Uint32(content[0:4])
+		response.Encoding = content[4]
+		response.Content = decodeText(content[5:], response.Encoding == EncodingUTF16)
+	}
+
+	return response, nil
+}
+
+type CommandResult struct {
+	Response CommandResponse
+	Err      error
+}
+
+// PendingCommand is a command sent to a device that is waiting for its response.
+type PendingCommand struct {
+	ServerFlag uint32
+	Imei       string
+	Command    string
+	Deadline   time.Time
+	done       chan CommandResult
+}
+
+// Done returns a channel that receives the result once the command is answered or has timed out.
+func (p *PendingCommand) Done() <-chan CommandResult {
+	return p.done
+}
+
+// Correlator matches online command responses to the commands that caused them by the server flag it
+// allocates for each command. Timeouts are only noticed by Expire, so a Correlator needs Run, or another
+// loop calling Expire, for Done to ever report ErrCommandTimeout.
+type Correlator struct {
+	mu      sync.Mutex
+	clock   Clock
+	serials *SerialGenerator
+	next    uint32
+	pending map[uint32]*PendingCommand
+}
+
+// NewCorrelator returns a Correlator that numbers the command packets of each device from serials. A nil
+// clock means time.Now and a nil serials a generator of its own.
+func NewCorrelator(clock Clock, serials *SerialGenerator) *Correlator {
+	if clock == nil {
+		clock = time.Now
+	}
+	if serials == nil {
+		serials = NewSerialGenerator()
+	}
+	return &Correlator{clock: clock, serials: serials, pending: make(map[uint32]*PendingCommand)}
+}
+
+// Send allocates a server flag for command, records it as pending until timeout and returns the packet
+// to write to the device. The packet takes the next serial number of imei unless command has one.
+func (c *Correlator) Send(imei string, command OnlineCommand, timeout time.Duration) (*PendingCommand, []byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for {
+		c.next++
+		if _, ok := c.pending[c.next]; c.next != 0 && !ok {
+			break
+		}
+	}
+	command.ServerFlag = c.next
+	if command.SerialNumber == 0 {
+		command.SerialNumber = c.serials.Next(imei)
+	}
+
+	packet, err := EncodeOnlineCommand(command)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pending := &PendingCommand{
+		ServerFlag: command.ServerFlag,
+		Imei:       imei,
+		Command:    command.Content,
+		Deadline:   c.clock().Add(timeout),
+		done:       make(chan CommandResult, 1),
+	}
+	c.pending[pending.ServerFlag] = pending
+
+	return pending, packet, nil
+}
+
+// Resolve decodes a response packet received from imei and completes the command it answers.
+func (c *Correlator) Resolve(imei string, input []byte) (*PendingCommand, error) {
+	response, err := DecodeCommandResponse(input)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	pending, ok := c.pending[response.ServerFlag]
+	if ok && pending.Imei != imei {
+		c.mu.Unlock()
+		return nil, ErrForeignServerFlag
+	}
+	delete(c.pending, response.ServerFlag)
+	c.mu.Unlock()
+
+	if !ok {
+		return nil, ErrUnknownServerFlag
+	}
+
+	pending.done <- CommandResult{Response: response}
+	return pending, nil
+}
+
+// Expire completes every command whose deadline has passed with ErrCommandTimeout and returns them.
+func (c *Correlator) Expire() []*PendingCommand {
+	now := c.clock()
+
+	c.mu.Lock()
+	var expired []*PendingCommand
+	for flag, pending := range c.pending {
+		if now.After(pending.Deadline) {
+			expired = append(expired, pending)
+			delete(c.pending, flag)
+		}
+	}
+	c.mu.Unlock()
+
+	for _, pending := range expired {
+		pending.done <- CommandResult{Err: ErrCommandTimeout}
+	}
+	return expired
+}
+
+// Run calls Expire every interval until ctx is done.
+func (c *Correlator) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.Expire()
+		}
+	}
+}
+
+// Pending returns the number of commands waiting for a response.
+func (c *Correlator) Pending() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.pending)
+}
@@ -0,0 +1,102 @@
+package concox
+
+import (
+	"context"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCorrelator(t *testing.T) {
+	now := time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC)
+	serials := NewSerialGenerator()
+	serials.Restore(map[string]uint16{"868120145233604": 41})
+	correlator := NewCorrelator(func() time.Time { return now }, serials)
+
+	relay, packet, err := correlator.Send("868120145233604", OnlineCommand{Content: "RELAY,1#"}, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(1), relay.ServerFlag)
+	assert.Equal(t, "0c00000001", hex.EncodeToString(packet[4:9]))
+	assert.Equal(t, uint16(42), GetPackageSn(packet))
+
+	where, packet, err := correlator.Send("868120145233604", OnlineCommand{Content: "WHERE#"}, 2*time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, uint16(43), GetPackageSn(packet))
+
+	_, packet, err = correlator.Send("356307042441013", OnlineCommand{Content: "WHERE#"}, 10*time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, uint16(1), GetPackageSn(packet))
+	assert.Equal(t, uint32(2), where.ServerFlag)
+	assert.Equal(t, 3, correlator.Pending())
+
+	text := hex.EncodeToString([]byte("Cut off the fuel supply: Success!"))
+	response := buildPacket(t, ParserOnlineCommandResponse, "25"+"00000001"+text+"0002", 9)
+	_, err = correlator.Resolve("356307042441013", response)
+	assert.ErrorIs(t, err, ErrForeignServerFlag)
+	resolved, err := correlator.Resolve("868120145233604", response)
+	require.NoError(t, err)
+	assert.Same(t, relay, resolved)
+	result := <-relay.Done()
+	require.NoError(t, result.Err)
+	assert.Equal(t, "Cut off the fuel supply: Success!", result.Response.Content)
+	assert.Equal(t, uint16(LanguageEnglish), result.Response.Language)
+
+	_, err = correlator.Resolve("868120145233604", response)
+	assert.ErrorIs(t, err, ErrUnknownServerFlag)
+
+	now = now.Add(90 * time.Second)
+	assert.Empty(t, correlator.Expire())
+	now = now.Add(time.Minute)
+	expired := correlator.Expire()
+	require.Len(t, expired, 1)
+	assert.Same(t, where, expired[0])
+	assert.ErrorIs(t, (<-where.Done()).Err, ErrCommandTimeout)
+	assert.Equal(t, 1, correlator.Pending())
+}
+
+func TestDecodeCommandResponseLong(t *testing.T) {
+	text := hex.EncodeToString([]byte("Lat:N23.111668,Lon:E114.409285"))
+	response, err := DecodeCommandResponse(buildLongPacket(t, ParserOnlineCommandLongResponse, "0000000501"+text, 3))
+	require.NoError(t, err)
+	assert.Equal(t, uint32(5), response.ServerFlag)
+	assert.Equal(t, byte(0x01), response.Encoding)
+	assert.Equal(t, "Lat:N23.111668,Lon:E114.409285", response.Content)
+}
+
+func TestDecodeCommandResponseEncoding(t *testing.T) {
+	// "已断油电" (oil and electricity cut) as UTF-16BE.
+	text := "5df265ad6cb97535"
+
+	response, err := DecodeCommandResponse(buildLongPacket(t, ParserOnlineCommandLongResponse, "00000009"+"02"+text, 4))
+	require.NoError(t, err)
+	assert.Equal(t, "已断油电", response.Content)
+
+	response, err = DecodeCommandResponse(buildPacket(t, ParserOnlineCommandResponse, "0c"+"00000009"+text+"0001", 5))
+	require.NoError(t, err)
+	assert.Equal(t, uint16(LanguageChinese), response.Language)
+	assert.Equal(t, "已断油电", response.Content)
+
+	response, err = DecodeCommandResponse(buildPacket(t, ParserOnlineCommandResponse, "08"+"00000009"+"4f4bff21"+"0002", 6))
+	require.NoError(t, err)
+	assert.Equal(t, "OK�!", response.Content)
+}
+
+func TestCorrelatorRun(t *testing.T) {
+	correlator := NewCorrelator(nil, nil)
+	pending, _, err := correlator.Send("868120145233604", OnlineCommand{Content: "WHERE#"}, time.Millisecond)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go correlator.Run(ctx, time.Millisecond)
+
+	select {
+	case result := <-pending.Done():
+		assert.ErrorIs(t, result.Err, ErrCommandTimeout)
+	case <-time.After(time.Second):
+		t.Fatal("command did not time out")
+	}
+}
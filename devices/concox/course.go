@@ -0,0 +1,52 @@
+package concox
+
+// CourseStatus is the two byte course/status word of the GPS block.
+//
+//	bit 15-14  unused
+//	bit 13     0 real-time GPS, 1 differential GPS
+//	bit 12     GPS positioned
+//	bit 11     0 east longitude, 1 west longitude
+//	bit 10     0 south latitude, 1 north latitude
+//	bit 9-0    course in degrees
+type CourseStatus struct {
+	Course       int  `json:"course"`
+	Differential bool `json:"differential"`
+	Positioned   bool `json:"positioned"`
+	West         bool `json:"west"`
+	North        bool `json:"north"`
+}
+
+// GpsInfo is the GPS information byte: the length of the GPS block in the high nibble and the number of
+// satellites in the low nibble.
+type GpsInfo struct {
+	Length     int `json:"length"`
+	Satellites int `json:"satellites"`
+}
+
+func DecodeCourseStatus(word uint16) CourseStatus {
+	return CourseStatus{
+		Course:       int(word & 0x03ff),
+		Differential: word&0x2000 != 0,
+		Positioned:   word&0x1000 != 0,
+		West:         word&0x0800 != 0,
+		North:        word&0x0400 != 0,
+	}
+}
+
+func DecodeGpsInfo(info byte) GpsInfo {
+	return GpsInfo{Length: int(info >> 4), Satellites: int(info & 0x0f)}
+}
+
+// Coordinates converts raw latitude and longitude values into signed degrees, negative for south latitude
+// and west longitude.
+func (c CourseStatus) Coordinates(latitude, longitude uint32) (float64, float64) {
+	lat := DecodeCoordinate(float64(latitude))
+	lng := DecodeCoordinate(float64(longitude))
+	if !c.North {
+		lat = -lat
+	}
+	if c.West {
+		lng = -lng
+	}
+	return lat, lng
+}
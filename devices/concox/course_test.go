@@ -0,0 +1,21 @@
+package concox
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeCourseStatus(t *testing.T) {
+	status := DecodeCourseStatus(0x3d4f)
+	assert.Equal(t, CourseStatus{Course: 0x14f, Differential: true, Positioned: true, West: true, North: true}, status)
+
+	latitude, longitude := status.Coordinates(0x027ac7eb, 0x0c465849)
+	assert.Equal(t, 23.111668, latitude)
+	assert.Equal(t, -114.409285, longitude)
+
+	latitude, _ = DecodeCourseStatus(0x1000).Coordinates(0x027ac7eb, 0x0c465849)
+	assert.Equal(t, -23.111668, latitude)
+
+	assert.Equal(t, GpsInfo{Length: 12, Satellites: 9}, DecodeGpsInfo(0xc9))
+}
@@ -0,0 +1,51 @@
+package concox
+
+// crcPolynomial is the CRC-ITU polynomial 0x1021, bit reversed.
+const crcPolynomial = 0x8408
+
+var crcTable = makeCrcTable()
+
+func makeCrcTable() [256]uint16 {
+	var table [256]uint16
+	for i := range table {
+		crc := uint16(i)
+		for bit := 0; bit < 8; bit++ {
+			if crc&1 != 0 {
+				crc = crc>>1 ^ crcPolynomial
+			} else {
+				crc >>= 1
+			}
+		}
+		table[i] = crc
+	}
+	return table
+}
+
+// Crc is a running CRC-16/X25, the CRC-ITU the packets carry. Start from NewCrc, feed it with Update and
+// read the result with Sum16:
+//
+//	crc := NewCrc().Update(header).Update(content)
+//	sum := crc.Sum16()
+type Crc uint16
+
+func NewCrc() Crc {
+	return 0xffff
+}
+
+// Update returns the CRC extended with data.
+func (c Crc) Update(data []byte) Crc {
+	for _, b := range data {
+		c = c>>8 ^ Crc(crcTable[byte(c)^b])
+	}
+	return c
+}
+
+// Sum16 returns the CRC of the data fed so far.
+func (c Crc) Sum16() uint16 {
+	return ^uint16(c)
+}
+
+// Checksum returns the CRC-16/X25 of data.
+func Checksum(data []byte) uint16 {
+	return NewCrc().Update(data).Sum16()
+}
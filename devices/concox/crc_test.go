@@ -0,0 +1,65 @@
+package concox
+
+import (
+	"testing"
+
+	"github.com/snksoft/crc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChecksum(t *testing.T) {
+	// The CRC-16/X25 check value.
+	assert.Equal(t, uint16(0x906e), Checksum([]byte("123456789")))
+
+	data := []byte{0x05, 0x01, 0x00, 0x01, 0xd9, 0xdc, 0x0d, 0x0a, 0x78}
+	for i := 0; i <= len(data); i++ {
+		assert.Equal(t, uint16(crc.CalculateCRC(crc.X25, data[:i])), Checksum(data[:i]))
+		assert.Equal(t, Checksum(data), NewCrc().Update(data[:i]).Update(data[i:]).Sum16())
+	}
+}
+
+func TestValidateAllocations(t *testing.T) {
+	packet := buildPacket(t, ParserLocationGT06, "0b081d112e10cc027ac7eb0c46584900148f01cc00287d001fb8", 3)
+	buf := make([]byte, 0, 64)
+	content := GetPackageContent(packet)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		if Validate(packet) != nil {
+			t.Fatal("invalid packet")
+		}
+		buf = AppendPackage(buf[:0], false, ParserLocationGT06, content, 3)
+	})
+	assert.Zero(t, allocs)
+	require.Equal(t, packet, buf)
+}
+
+var benchmarkPacket = []byte{
+	0x78, 0x78, 0x1f, 0x12, 0x0b, 0x08, 0x1d, 0x11, 0x2e, 0x10, 0xcc, 0x02, 0x7a, 0xc7, 0xeb, 0x0c, 0x46, 0x58,
+	0x49, 0x00, 0x14, 0x8f, 0x01, 0xcc, 0x00, 0x28, 0x7d, 0x00, 0x1f, 0xb8, 0x00, 0x03,
+}
+
+func BenchmarkChecksum(b *testing.B) {
+	b.ReportAllocs()
+	b.SetBytes(int64(len(benchmarkPacket)))
+	for i := 0; i < b.N; i++ {
+		Checksum(benchmarkPacket[2:])
+	}
+}
+
+func BenchmarkSnksoftCRC(b *testing.B) {
+	b.ReportAllocs()
+	b.SetBytes(int64(len(benchmarkPacket)))
+	for i := 0; i < b.N; i++ {
+		crc.CalculateCRC(crc.X25, benchmarkPacket[2:])
+	}
+}
+
+func BenchmarkCrcChecker(b *testing.B) {
+	packet := EncodePackage(false, ParserLocationGT06, benchmarkPacket[4:30], 3)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		CrcChecker(packet)
+	}
+}
@@ -0,0 +1,42 @@
+package concox
+
+// TimeCalibrationRequest is a time calibration request (0x8a), which carries nothing but its serial number.
+type TimeCalibrationRequest struct {
+	SerialNumber uint16 `json:"serialNumber"`
+}
+
+// Decode validates a packet and decodes it with the decoder of its protocol number. The result is one of
+// Login, generics.Location, LocationX3, generics.HeartBeat, generics.Alarm, generics.LbsLocation,
+// generics.WifiLocation, InformationContent, CommandResponse or TimeCalibrationRequest.
+//
+// The decoders fill what the packet carries; DeviceId, CreatedAt and UpdatedAt are left for the caller.
+func Decode(input []byte) (interface{}, error) {
+	if err := Validate(input); err != nil {
+		return nil, err
+	}
+
+	switch GetPackageType(input) {
+	case ParserLogin:
+		return DecodeLogin(input)
+	case ParserLocationGT06:
+		return DecodeLocation(input)
+	case ParserLocationX3:
+		return DecodeLocationX3(input)
+	case ParserStatus:
+		return DecodeHeartBeat(input)
+	case ParserAlarmGT06, ParserAlarmX3, ParserAlarmX3V2:
+		return DecodeAlarm(input)
+	case ParserLBSLocationGT06, ParserLBSLocationX3:
+		return DecodeLbsLocation(input)
+	case ParserWifiInformation:
+		return DecodeWifiLocation(input)
+	case ParserInformation:
+		return DecodeInformation(input)
+	case ParserOnlineCommandResponse, ParserOnlineCommandLongResponse:
+		return DecodeCommandResponse(input)
+	case ParserTimeCalibration:
+		return TimeCalibrationRequest{SerialNumber: GetPackageSn(input)}, nil
+	}
+
+	return nil, ErrUnknownProtocol
+}
@@ -0,0 +1,367 @@
+package concox
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/xen0tic/utils/generics"
+	"golang.org/x/exp/slices"
+)
+
+// Field is one dissected field of a packet. Valid is false when the field fails its check or the packet
+// ends before it.
+type Field struct {
+	Offset int    `json:"offset"`
+	Length int    `json:"length"`
+	Name   string `json:"name"`
+	Raw    string `json:"raw"`
+	Value  string `json:"value"`
+	Valid  bool   `json:"valid"`
+}
+
+var protocolNames = map[byte]string{
+	ParserLogin:                     ParserLoginStr,
+	ParserLocationGT06:              ParserLocationStr,
+	ParserLocationX3:                ParserLocationStr,
+	ParserStatus:                    ParserStatusStr,
+	ParserAlarmGT06:                 ParserAlarmStr,
+	ParserAlarmX3:                   ParserAlarmStr,
+	ParserAlarmX3V2:                 ParserAlarmStr,
+	ParserWifiInformation:           ParserWifiInformationStr,
+	ParserLBSLocationGT06:           ParserLBSLocationStr,
+	ParserLBSLocationX3:             ParserLBSLocationStr,
+	ParserInformation:               ParserInformationStr,
+	ParserTimeCalibration:           ParserTimeCalibrationStr,
+	ParserOnlineCommandResponse:     ParserOnlineCommandResponseStr,
+	ParserOnlineCommandLongResponse: ParserOnlineCommandLongResponseStr,
+	ParserOnlineCommandProtocol:     "Online Command",
+}
+
+// ProtocolString returns the name of a protocol number, "Unknown" for numbers this package does not know.
+func ProtocolString(protocol byte) string {
+	if name, ok := protocolNames[protocol]; ok {
+		return name
+	}
+	return "Unknown"
+}
+
+type dissector struct {
+	input  []byte
+	offset int
+	end    int
+	fields []Field
+}
+
+// Dissect splits a packet into its fields in wire order, decoding each one it knows. It never fails:
+// fields the packet is too short for are reported as invalid.
+func Dissect(input []byte) []Field {
+	d := &dissector{input: input, end: len(input) - 6}
+
+	long := IsLongPackage(input)
+	d.add("Start Bit", 2, func(raw []byte) (string, bool) {
+		switch {
+		case long:
+			return "long packet", true
+		case IsNormalPackage(raw):
+			return "packet", true
+		}
+		return "unknown", false
+	})
+
+	lengthSize := 1
+	if long {
+		lengthSize = 2
+	}
+	d.add("Packet Length", lengthSize, func(raw []byte) (string, bool) {
+		size, _ := frameLength(input)
+		return strconv.Itoa(size - 4 - len(raw)), size == len(input)
+	})
+
+	protocol, _ := PackageType(input)
+	d.add("Protocol Number", 1, func(raw []byte) (string, bool) {
+		_, ok := protocolNames[raw[0]]
+		return fmt.Sprintf("0x%02x %s", raw[0], ProtocolString(raw[0])), ok
+	})
+
+	if d.end > d.offset {
+		d.content(protocol)
+		if d.offset < d.end {
+			d.add("Unparsed", d.end-d.offset, nil)
+		}
+	}
+
+	d.end = len(input)
+	d.add("Serial Number", 2, func(raw []byte) (string, bool) {
+		return strconv.Itoa(int(binary.BigEndian.Uint16(raw))), true
+	})
+	d.add("CRC", 2, func(raw []byte) (string, bool) {
+		return "", CrcChecker(input)
+	})
+	d.add("Stop Bit", 2, func(raw []byte) (string, bool) {
+		return "", ValidateEndBytes(input)
+	})
+
+	return d.fields
+}
+
+// RenderDissection renders fields as an aligned table, one field per line, marking invalid fields with
+// an exclamation mark.
+func RenderDissection(fields []Field) string {
+	var buf bytes.Buffer
+
+	w := tabwriter.NewWriter(&buf, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "\tOffset\tLength\tField\tRaw\tValue")
+	for _, field := range fields {
+		mark := ""
+		if !field.Valid {
+			mark = "!"
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%04d\t%d\t%s\t%s\t%s\n", mark, field.Offset, field.Length, field.Name, field.Raw, field.Value)
+	}
+	_ = w.Flush()
+
+	return buf.String()
+}
+
+// add appends the next n bytes as a field. value may be nil for fields that have no decoded form.
+func (d *dissector) add(name string, n int, value func(raw []byte) (string, bool)) []byte {
+	field := Field{Offset: d.offset, Length: n, Name: name}
+
+	if d.offset < 0 || n < 0 || d.offset+n > d.end {
+		field.Length = 0
+		if d.offset >= 0 && d.offset < d.end {
+			field.Raw = hex.EncodeToString(d.input[d.offset:d.end])
+			field.Length = d.end - d.offset
+		}
+		field.Value = "truncated"
+		d.fields = append(d.fields, field)
+		d.offset = d.end
+		return nil
+	}
+
+	raw := d.input[d.offset : d.offset+n]
+	field.Raw = hex.EncodeToString(raw)
+	field.Valid = true
+	if value != nil {
+		field.Value, field.Valid = value(raw)
+	}
+
+	d.fields = append(d.fields, field)
+	d.offset += n
+	return raw
+}
+
+func (d *dissector) remaining() int {
+	return d.end - d.offset
+}
+
+func (d *dissector) content(protocol byte) {
+	switch protocol {
+	case ParserLogin:
+		d.add("IMEI", 8, func(raw []byte) (string, bool) {
+			imei, err := generics.DecodeIMEIBCD(raw)
+			if errors.Is(err, generics.ErrImeiCheckDigit) {
+				return imei.String() + " (bad check digit)", false
+			}
+			if err != nil {
+				return err.Error(), false
+			}
+			return imei.String(), true
+		})
+		if d.remaining() >= 2 {
+			d.add("Model Code", 2, hexValue)
+		}
+		if d.remaining() >= 2 {
+			d.add("Time Zone Language", 2, func(raw []byte) (string, bool) {
+				login := Login{}
+				login.TimeZoneOffset, login.Language = decodeTimeZone(binary.BigEndian.Uint16(raw))
+				return fmt.Sprintf("%s, %s", login.Location(), LanguageString(login.Language)), true
+			})
+		}
+	case ParserLocationGT06, ParserLocationX3:
+		d.gps()
+		d.cell()
+		if d.remaining() > 0 {
+			d.add("ACC", 1, func(raw []byte) (string, bool) { return onOff(raw[0] != 0), true })
+		}
+		if protocol == ParserLocationX3 {
+			d.add("Upload Mode", 1, func(raw []byte) (string, bool) { return UploadModeString(raw[0]), true })
+			d.add("Real Time / Re-upload", 1, func(raw []byte) (string, bool) {
+				if raw[0] == 0x01 {
+					return "re-upload", true
+				}
+				return "real time", true
+			})
+			if d.remaining() >= 4 {
+				d.add("Mileage", 4, uint32Value)
+			}
+		}
+	case ParserStatus:
+		d.status()
+		if d.remaining() >= 2 {
+			d.add("Language", 2, func(raw []byte) (string, bool) { return LanguageString(raw[1]), true })
+		}
+	case ParserAlarmGT06, ParserAlarmX3, ParserAlarmX3V2:
+		d.gps()
+		lbsLength := d.add("LBS Length", 1, uint8Value)
+		if lbsLength != nil && lbsLength[0] > 0 {
+			start := d.offset
+			d.cell()
+			if rest := start + int(lbsLength[0]) - 1 - d.offset; rest > 0 {
+				d.add("LBS Extra", rest, nil)
+			}
+		}
+		d.status()
+		d.add("Alarm", 1, func(raw []byte) (string, bool) { return AlarmModeString(raw[0]), true })
+		d.add("Language", 1, func(raw []byte) (string, bool) { return LanguageString(raw[0]), true })
+		if protocol == ParserAlarmX3V2 {
+			d.add("Fence Number", 1, uint8Value)
+		}
+	case ParserLBSLocationGT06, ParserLBSLocationX3, ParserWifiInformation:
+		d.add("Date Time", dateTimeLength, dateTimeValue)
+		d.cell()
+		d.add("RSSI", 1, uint8Value)
+		for i := 1; i <= lbsNeighbourCount; i++ {
+			d.add(fmt.Sprintf("Neighbour %d LAC", i), 2, uint16Value)
+			d.add(fmt.Sprintf("Neighbour %d Cell ID", i), 3, uint24Value)
+			d.add(fmt.Sprintf("Neighbour %d RSSI", i), 1, uint8Value)
+		}
+		d.add("Time Advance", 1, uint8Value)
+		if protocol != ParserWifiInformation {
+			d.add("Language", 2, func(raw []byte) (string, bool) { return LanguageString(raw[1]), true })
+			break
+		}
+		count := d.add("WiFi Count", 1, uint8Value)
+		for i := 1; count != nil && i <= int(count[0]); i++ {
+			d.add(fmt.Sprintf("WiFi %d BSSID", i), 6, func(raw []byte) (string, bool) {
+				return fmt.Sprintf("%02x:%02x:%02x:%02x:%02x:%02x", raw[0], raw[1], raw[2], raw[3], raw[4], raw[5]), true
+			})
+			d.add(fmt.Sprintf("WiFi %d RSSI", i), 1, uint8Value)
+		}
+	case ParserInformation:
+		infoType := d.add("Information Type", 1, hexValue)
+		if infoType != nil && d.remaining() > 0 {
+			d.add("Information Content", d.remaining(), func(raw []byte) (string, bool) {
+				info, err := DecodeInformation(d.input)
+				if err != nil {
+					return err.Error(), false
+				}
+				return info.String(), true
+			})
+		}
+	case ParserOnlineCommandResponse, ParserOnlineCommandProtocol:
+		length := d.add("Command Length", 1, uint8Value)
+		d.add("Server Flag", 4, uint32Value)
+		if length != nil && int(length[0]) >= 4 {
+			size := int(length[0]) - 4
+			next := d.offset + size + 1
+			unicode := protocol == ParserOnlineCommandResponse && next < d.end && d.input[next] == LanguageChinese
+			d.add("Command Content", size, textValue(unicode))
+		}
+		if d.remaining() >= 2 {
+			d.add("Language", 2, func(raw []byte) (string, bool) { return LanguageString(raw[1]), true })
+		}
+	case ParserOnlineCommandLongResponse:
+		d.add("Server Flag", 4, uint32Value)
+		encoding := d.add("Content Encoding", 1, hexValue)
+		if encoding != nil && d.remaining() > 0 {
+			d.add("Content", d.remaining(), textValue(encoding[0] == EncodingUTF16))
+		}
+	case ParserTimeCalibration:
+	default:
+		if !slices.Contains(validPackage(), protocol) && d.remaining() > 0 {
+			d.add("Content", d.remaining(), nil)
+		}
+	}
+}
+
+func (d *dissector) gps() {
+	d.add("Date Time", dateTimeLength, dateTimeValue)
+	d.add("GPS Info", 1, func(raw []byte) (string, bool) {
+		info := DecodeGpsInfo(raw[0])
+		return fmt.Sprintf("length %d, %d satellites", info.Length, info.Satellites), true
+	})
+	latitude := d.add("Latitude", 4, func(raw []byte) (string, bool) {
+		return strconv.FormatFloat(DecodeCoordinate(float64(binary.BigEndian.Uint32(raw))), 'f', 6, 64), true
+	})
+	longitude := d.add("Longitude", 4, func(raw []byte) (string, bool) {
+		return strconv.FormatFloat(DecodeCoordinate(float64(binary.BigEndian.Uint32(raw))), 'f', 6, 64), true
+	})
+	d.add("Speed", 1, uint8Value)
+	d.add("Course Status", 2, func(raw []byte) (string, bool) {
+		status := DecodeCourseStatus(binary.BigEndian.Uint16(raw))
+		value := fmt.Sprintf("course %d, positioned %t, differential %t", status.Course, status.Positioned, status.Differential)
+		if latitude != nil && longitude != nil {
+			lat, lng := status.Coordinates(binary.BigEndian.Uint32(latitude), binary.BigEndian.Uint32(longitude))
+			value += fmt.Sprintf(", position %.6f,%.6f", lat, lng)
+		}
+		return value, true
+	})
+}
+
+func (d *dissector) cell() {
+	mcc := d.add("MCC", 2, func(raw []byte) (string, bool) {
+		return strconv.Itoa(int(binary.BigEndian.Uint16(raw) & 0x7fff)), true
+	})
+	if mcc != nil && mcc[0]&0x80 != 0 {
+		d.add("MNC", 2, uint16Value)
+	} else {
+		d.add("MNC", 1, uint8Value)
+	}
+	d.add("LAC", 2, uint16Value)
+	d.add("Cell ID", 3, uint24Value)
+}
+
+func (d *dissector) status() {
+	d.add("Terminal Information", 1, func(raw []byte) (string, bool) {
+		info := DecodeTerminalInfo(raw[0])
+		return fmt.Sprintf("relay cut %t, gps tracking %t, alarm %s, charging %t, acc %s, defense %t",
+			info.RelayState, info.GpsTracking, info.Alarm.String, info.Charging, onOff(info.Ignition), info.Status), true
+	})
+	d.add("Voltage Level", 1, func(raw []byte) (string, bool) { return DecodeVoltageLevel(raw[0]).String, true })
+	d.add("GSM Signal", 1, func(raw []byte) (string, bool) { return DecodeGsmSignal(raw[0]).String, true })
+}
+
+func dateTimeValue(raw []byte) (string, bool) {
+	t := decodeDateTime(raw)
+	valid := int(raw[1]) == int(t.Month()) && int(raw[2]) == t.Day() && int(raw[3]) == t.Hour()
+	return t.Format(generics.DateFormat) + " UTC", valid
+}
+
+func hexValue(raw []byte) (string, bool) {
+	return "0x" + hex.EncodeToString(raw), true
+}
+
+func textValue(unicode bool) func(raw []byte) (string, bool) {
+	return func(raw []byte) (string, bool) {
+		return strconv.Quote(decodeText(raw, unicode)), true
+	}
+}
+
+func uint8Value(raw []byte) (string, bool) {
+	return strconv.Itoa(int(raw[0])), true
+}
+
+func uint16Value(raw []byte) (string, bool) {
+	return strconv.Itoa(int(binary.BigEndian.Uint16(raw))), true
+}
+
+func uint24Value(raw []byte) (string, bool) {
+	return strconv.Itoa(int(decodeUint24(raw))), true
+}
+
+func uint32Value(raw []byte) (string, bool) {
+	return strconv.FormatUint(uint64(binary.BigEndian.Uint32(raw)), 10), true
+}
+
+func onOff(on bool) string {
+	if on {
+		return "on"
+	}
+	return "off"
+}
@@ -0,0 +1,67 @@
+package concox
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func fieldNames(fields []Field) []string {
+	names := make([]string, len(fields))
+	for i, field := range fields {
+		names[i] = field.Name
+	}
+	return names
+}
+
+func TestDissect(t *testing.T) {
+	login := buildPacket(t, ParserLogin, "0868120145233604"+"3604"+"14a2", 2)
+	fields := Dissect(login)
+	assert.Equal(t, []string{
+		"Start Bit", "Packet Length", "Protocol Number", "IMEI", "Model Code", "Time Zone Language",
+		"Serial Number", "CRC", "Stop Bit",
+	}, fieldNames(fields))
+	assert.Equal(t, Field{Offset: 4, Length: 8, Name: "IMEI", Raw: "0868120145233604", Value: "868120145233604", Valid: true}, fields[3])
+	assert.Equal(t, "GMT+03:30, English", fields[5].Value)
+
+	offset := 0
+	for _, field := range fields {
+		assert.True(t, field.Valid, field.Name)
+		assert.Equal(t, offset, field.Offset, field.Name)
+		offset += field.Length
+	}
+	assert.Equal(t, len(login), offset)
+
+	location := buildPacket(t, ParserLocationGT06, "0b081d112e10cc027ac7eb0c46584900148f01cc00287d001fb8", 3)
+	fields = Dissect(location)
+	require.Len(t, fields, 16)
+	assert.Equal(t, "0x12 Location", fields[2].Value)
+	assert.Equal(t, "2011-08-29 17:46:16 UTC", fields[3].Value)
+	assert.Equal(t, "23.111668", fields[5].Value)
+	assert.Equal(t, "460", fields[9].Value)
+
+	location[len(location)-3]++
+	fields = Dissect(location)
+	assert.False(t, fields[len(fields)-2].Valid)
+
+	alarm := buildPacket(t, ParserAlarmX3V2, "0b081d112e10cc027ac7eb0c46584900148f0901cc00287d001fb844040305020a", 0x0b)
+	fields = Dissect(alarm)
+	assert.Equal(t, "Fence Number", fields[len(fields)-4].Name)
+	assert.Equal(t, "10", fields[len(fields)-4].Value)
+
+	truncated := Dissect(location[:12])
+	assert.Equal(t, Field{Offset: 4, Length: 2, Name: "Date Time", Raw: "0b08", Value: "truncated"}, truncated[3])
+	assert.False(t, truncated[len(truncated)-1].Valid)
+}
+
+func TestRenderDissection(t *testing.T) {
+	output := RenderDissection(Dissect(buildPacket(t, ParserStatus, "4405040002", 5)))
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	require.Len(t, lines, 11)
+	assert.Contains(t, lines[0], "Offset")
+	assert.Contains(t, lines[6], "GSM Signal")
+	assert.Contains(t, lines[6], "Strong")
+	assert.NotContains(t, output, "!")
+}
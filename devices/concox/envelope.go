@@ -0,0 +1,100 @@
+package concox
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"github.com/xen0tic/utils/generics"
+)
+
+const (
+	siteHeaderLength  = 4
+	siteRequestLength = 4
+	siteImeiLength    = 8
+	siteMinLength     = siteHeaderLength + siteRequestLength + siteImeiLength + 4
+	siteMaxCommand    = 0xffff - siteRequestLength - siteImeiLength - 2
+)
+
+// ErrBadImei occurs when the IMEI of a site command is not 15 digits or fails its Luhn check.
+var ErrBadImei = errors.New("concox: invalid IMEI")
+
+// SiteCommand is the envelope the web panel sends to the gateway to have a command delivered to a device.
+//
+//	0x70 0x70     start bytes
+//	length (2)    bytes from the request ID through the CRC
+//	request (4)   request ID chosen by the site, echoed in its result
+//	IMEI (8)      BCD, a leading zero nibble followed by the 15 digits
+//	command (n)   command text, e.g. "RELAY,1#"
+//	CRC (2)       CRC-ITU from the length through the command
+//	0x0d 0x0a     stop bytes
+type SiteCommand struct {
+	RequestID uint32        `json:"requestId"`
+	Imei      generics.Imei `json:"imei"`
+	Command   string        `json:"command"`
+}
+
+func EncodeSiteCommand(command SiteCommand) ([]byte, error) {
+	if _, err := generics.ParseIMEI(command.Imei.String()); err != nil {
+		return nil, ErrBadImei
+	}
+	imei, err := command.Imei.BCD()
+	if err != nil {
+		return nil, ErrBadImei
+	}
+	if len(command.Command) > siteMaxCommand {
+		return nil, ErrCommandTooLong
+	}
+
+	length := siteRequestLength + siteImeiLength + len(command.Command) + 2
+
+	result := make([]byte, 0, length+6)
+	result = append(result, ParserOnlineCommandRequestStartBit, ParserOnlineCommandRequestStartBit)
+	result = binary.BigEndian.AppendUint16(result, uint16(length))
+	result = binary.BigEndian.AppendUint32(result, command.RequestID)
+	result = append(result, imei...)
+	result = append(result, command.Command...)
+
+	crc1, crc2 := GenerateCrc(result[2:])
+
+	return append(result, crc1, crc2, ParserEndBitFirst, ParserEndBitEnd), nil
+}
+
+// ValidateSiteCommand checks the framing, length and CRC of a site command envelope.
+func ValidateSiteCommand(input []byte) error {
+	if len(input) < siteMinLength {
+		return ErrShortPacket
+	}
+	if !IsPacketFromSite(input) {
+		return ErrBadStart
+	}
+	if !ValidateEndBytes(input) {
+		return ErrBadStop
+	}
+	if int(binary.BigEndian.Uint16(input[2:4]))+6 != len(input) {
+		return ErrLengthMismatch
+	}
+	if !CrcChecker(input) {
+		return ErrCRC
+	}
+	return nil
+}
+
+func DecodeSiteCommand(input []byte) (SiteCommand, error) {
+	var command SiteCommand
+
+	if err := ValidateSiteCommand(input); err != nil {
+		return command, err
+	}
+
+	body := input[siteHeaderLength : len(input)-4]
+	imei, err := generics.DecodeIMEIBCD(body[siteRequestLength : siteRequestLength+siteImeiLength])
+	if err != nil {
+		return command, ErrBadImei
+	}
+
+	command.RequestID = binary.BigEndian.Uint32(body[:siteRequestLength])
+	command.Imei = imei
+	command.Command = string(body[siteRequestLength+siteImeiLength:])
+
+	return command, nil
+}
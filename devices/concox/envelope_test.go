@@ -0,0 +1,31 @@
+package concox
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSiteCommand(t *testing.T) {
+	command := SiteCommand{RequestID: 42, Imei: "868120145233604", Command: "RELAY,1#"}
+	packet, err := EncodeSiteCommand(command)
+	require.NoError(t, err)
+	assert.True(t, IsPacketFromSite(packet))
+	assert.Equal(t, "70700016"+"0000002a"+"0868120145233604", hex.EncodeToString(packet[:16]))
+
+	decoded, err := DecodeSiteCommand(packet)
+	require.NoError(t, err)
+	assert.Equal(t, command, decoded)
+
+	corrupt := append([]byte(nil), packet...)
+	corrupt[10] ^= 0x01
+	_, err = DecodeSiteCommand(corrupt)
+	assert.ErrorIs(t, err, ErrCRC)
+	_, err = DecodeSiteCommand(packet[:len(packet)-3])
+	assert.Error(t, err)
+
+	_, err = EncodeSiteCommand(SiteCommand{Imei: "868120145233605"})
+	assert.ErrorIs(t, err, ErrBadImei)
+}
@@ -0,0 +1,22 @@
+package concox
+
+import "errors"
+
+var (
+	// ErrShortPacket occurs when a packet is too short for the fields its protocol number requires.
+	ErrShortPacket = errors.New("concox: packet is too short")
+	// ErrUnexpectedProtocol occurs when a decoder is given a packet of another protocol number.
+	ErrUnexpectedProtocol = errors.New("concox: unexpected protocol number")
+	// ErrBadStart occurs when a packet does not begin with 0x78 0x78 or 0x79 0x79.
+	ErrBadStart = errors.New("concox: packet has no start bytes")
+	// ErrBadStop occurs when a packet does not end with 0x0d 0x0a.
+	ErrBadStop = errors.New("concox: packet has no stop bytes")
+	// ErrLengthMismatch occurs when the length field of a packet does not match its size.
+	ErrLengthMismatch = errors.New("concox: packet length does not match its length field")
+	// ErrCRC occurs when the CRC of a packet does not match its content.
+	ErrCRC = errors.New("concox: packet CRC mismatch")
+	// ErrUnknownProtocol occurs when a packet carries a protocol number this package does not accept.
+	ErrUnknownProtocol = errors.New("concox: unknown protocol number")
+	// ErrCommandTooLong occurs when an online command does not fit the command length byte.
+	ErrCommandTooLong = errors.New("concox: command is too long")
+)
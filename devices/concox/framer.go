@@ -0,0 +1,185 @@
+package concox
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+const (
+	// minFrameLength is the size of a short packet with an empty content.
+	minFrameLength = 10
+	// maxFrameLength bounds the size a length field may claim. The largest packets devices send, WiFi
+	// scans and information uploads, stay well under it.
+	maxFrameLength = 1024
+)
+
+// Discard reports bytes the Framer dropped and why.
+type Discard struct {
+	Bytes  int
+	Reason error
+}
+
+// Framer splits a device TCP stream into whole packets. It keeps partial packets between calls to Feed
+// and, on corruption, drops bytes until the next 0x78 0x78 or 0x79 0x79 start.
+//
+// A Framer is not safe for concurrent use, it is meant to be held per connection.
+type Framer struct {
+	buf       []byte
+	discarded int
+}
+
+func NewFramer() *Framer {
+	return new(Framer)
+}
+
+// Feed appends chunk to the buffered bytes and returns every whole, validated packet it completes along
+// with the bytes it had to discard on the way.
+func (f *Framer) Feed(chunk []byte) ([][]byte, []Discard) {
+	f.buf = append(f.buf, chunk...)
+
+	var frames [][]byte
+	var discards []Discard
+	discard := func(n int, reason error) {
+		f.buf = f.buf[n:]
+		f.discarded += n
+		if last := len(discards) - 1; last >= 0 && discards[last].Reason == reason {
+			discards[last].Bytes += n
+			return
+		}
+		discards = append(discards, Discard{Bytes: n, Reason: reason})
+	}
+
+	for len(f.buf) > 0 {
+		start := indexStart(f.buf)
+		if start < 0 {
+			// Keep a trailing start byte, the second one may come with the next chunk.
+			n := len(f.buf)
+			if last := f.buf[n-1]; last == ParserStartBit || last == ParserLongStartBit {
+				n--
+			}
+			if n > 0 {
+				discard(n, ErrBadStart)
+			}
+			break
+		}
+		if start > 0 {
+			discard(start, ErrBadStart)
+		}
+
+		size, ok := frameLength(f.buf)
+		if !ok {
+			break
+		}
+		if size < minFrameLength || size > maxFrameLength {
+			discard(2, ErrLengthMismatch)
+			continue
+		}
+		if len(f.buf) < size {
+			// A corrupt length field claims bytes that may never come. A whole packet at a later start
+			// proves it wrong, so the scan moves on instead of waiting.
+			if next := f.nextFrame(); next > 0 {
+				discard(next, ErrLengthMismatch)
+				continue
+			}
+			break
+		}
+
+		frame := f.buf[:size]
+		switch err := Validate(frame); err {
+		case nil:
+			frames = append(frames, append([]byte(nil), frame...))
+			f.buf = f.buf[size:]
+		case ErrBadStop:
+			// The length field is likely corrupt, so only the start bytes are dropped and the scan goes on.
+			discard(2, err)
+		default:
+			discard(size, err)
+		}
+	}
+
+	f.buf = append(f.buf[:0:0], f.buf...)
+
+	return frames, discards
+}
+
+// Flush ends the stream: the partial packet at the front is given up on, the bytes after it are scanned
+// again and any packets they hold are returned. The Framer is empty afterwards.
+func (f *Framer) Flush() ([][]byte, []Discard) {
+	var frames [][]byte
+	var discards []Discard
+
+	for {
+		out, dropped := f.Feed(nil)
+		frames = append(frames, out...)
+		discards = append(discards, dropped...)
+		if len(f.buf) == 0 {
+			return frames, discards
+		}
+
+		n := 2
+		if len(f.buf) < n {
+			n = len(f.buf)
+		}
+		f.buf = f.buf[n:]
+		f.discarded += n
+		discards = append(discards, Discard{Bytes: n, Reason: ErrShortPacket})
+	}
+}
+
+// nextFrame returns the offset of the first start after the front of the buffer that begins a whole,
+// valid packet, or 0 when there is none.
+func (f *Framer) nextFrame() int {
+	for offset := 2; offset < len(f.buf); offset++ {
+		i := indexStart(f.buf[offset:])
+		if i < 0 {
+			return 0
+		}
+		offset += i
+
+		size, ok := frameLength(f.buf[offset:])
+		if ok && size >= minFrameLength && offset+size <= len(f.buf) && Validate(f.buf[offset:offset+size]) == nil {
+			return offset
+		}
+	}
+	return 0
+}
+
+// Buffered returns the number of bytes waiting for the rest of their packet.
+func (f *Framer) Buffered() int {
+	return len(f.buf)
+}
+
+// Bytes returns a copy of the bytes waiting for the rest of their packet.
+func (f *Framer) Bytes() []byte {
+	return append([]byte(nil), f.buf...)
+}
+
+// Discarded returns the number of bytes dropped since the Framer was created.
+func (f *Framer) Discarded() int {
+	return f.discarded
+}
+
+func indexStart(input []byte) int {
+	short := bytes.Index(input, []byte{ParserStartBit, ParserStartBit})
+	long := bytes.Index(input, []byte{ParserLongStartBit, ParserLongStartBit})
+	if short < 0 || (long >= 0 && long < short) {
+		return long
+	}
+	return short
+}
+
+// frameLength returns the full size of the packet at the beginning of input, or false when the length
+// field has not arrived yet.
+func frameLength(input []byte) (int, bool) {
+	if IsLongPackage(input) {
+		if len(input) < 4 {
+			return 0, false
+		}
+		return int(binary.BigEndian.Uint16(input[2:4])) + 6, true
+	}
+
+	if len(input) < 3 {
+		return 0, false
+	}
+	return int(input[2]) + 5, true
+}
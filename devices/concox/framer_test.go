@@ -0,0 +1,133 @@
+package concox
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFramer(t *testing.T) {
+	login := buildPacket(t, ParserLogin, "0868120145233604", 1)
+	status := buildPacket(t, ParserStatus, "c606000002", 2)
+	info := buildLongPacket(t, ParserInformation, "0004d2", 3)
+
+	stream := append(append(append([]byte{}, login...), status...), info...)
+
+	framer := NewFramer()
+	var frames [][]byte
+	for i := 0; i < len(stream); i += 7 {
+		end := i + 7
+		if end > len(stream) {
+			end = len(stream)
+		}
+		out, discards := framer.Feed(stream[i:end])
+		assert.Empty(t, discards)
+		frames = append(frames, out...)
+	}
+	require.Len(t, frames, 3)
+	assert.Equal(t, login, frames[0])
+	assert.Equal(t, status, frames[1])
+	assert.Equal(t, info, frames[2])
+	assert.Zero(t, framer.Buffered())
+
+	corrupt := append([]byte(nil), status...)
+	corrupt[5] ^= 0xff
+	garbage := []byte{0x01, 0x02, 0x03}
+	stream = append(append(append(append([]byte{}, garbage...), corrupt...), login...), status[:6]...)
+
+	frames, discards := framer.Feed(stream)
+	require.Len(t, frames, 1)
+	assert.Equal(t, login, frames[0])
+	assert.Equal(t, []Discard{{Bytes: 3, Reason: ErrBadStart}, {Bytes: len(status), Reason: ErrCRC}}, discards)
+	assert.Equal(t, 6, framer.Buffered())
+	assert.Equal(t, 3+len(status), framer.Discarded())
+
+	frames, discards = framer.Feed(status[6:])
+	assert.Empty(t, discards)
+	require.Len(t, frames, 1)
+	assert.Equal(t, status, frames[0])
+
+	badStop := append([]byte(nil), login...)
+	badStop[len(badStop)-1] = 0x00
+	frames, discards = framer.Feed(append(badStop, status...))
+	require.Len(t, frames, 1)
+	assert.Equal(t, status, frames[0])
+	assert.Equal(t, ErrBadStop, discards[0].Reason)
+
+	frames, _ = framer.Feed([]byte{0x78, 0x78, 0x00})
+	assert.Empty(t, frames)
+	assert.NotPanics(t, func() { framer.Feed([]byte{0x78}) })
+}
+
+func TestValidate(t *testing.T) {
+	login := buildPacket(t, ParserLogin, "0868120145233604", 1)
+	require.NoError(t, Validate(login))
+
+	modify := func(f func(p []byte) []byte) []byte {
+		return f(append([]byte(nil), login...))
+	}
+
+	assert.ErrorIs(t, Validate(login[:5]), ErrShortPacket)
+	assert.ErrorIs(t, Validate(modify(func(p []byte) []byte { p[0] = 0x70; return p })), ErrBadStart)
+	assert.ErrorIs(t, Validate(modify(func(p []byte) []byte { p[len(p)-1] = 0x00; return p })), ErrBadStop)
+	assert.ErrorIs(t, Validate(modify(func(p []byte) []byte { p[2]++; return p })), ErrLengthMismatch)
+	assert.ErrorIs(t, Validate(modify(func(p []byte) []byte { p[3] = 0x55; return p })), ErrUnknownProtocol)
+	assert.ErrorIs(t, Validate(modify(func(p []byte) []byte { p[6] ^= 0xff; return p })), ErrCRC)
+	assert.False(t, ValidatePackage(login[:5]))
+
+	for i := 0; i <= len(login); i++ {
+		assert.NotPanics(t, func() {
+			ValidatePackage(login[:i])
+			CrcChecker(login[:i])
+			GetPackageType(login[:i])
+			GetPackageSn(login[:i])
+		})
+	}
+	_, err := PackageType([]byte{ParserLongStartBit, ParserLongStartBit, 0x00, 0x05})
+	assert.ErrorIs(t, err, ErrShortPacket)
+}
+
+func TestFramerCorruptLength(t *testing.T) {
+	login := buildPacket(t, ParserLogin, "0868120145233604", 1)
+
+	var stream []byte
+	for i := 0; i < 100; i++ {
+		stream = append(stream, login...)
+	}
+
+	for _, header := range [][]byte{{0x79, 0x79, 0xff, 0xff}, {0x79, 0x79, 0x01, 0x00}, {0x78, 0x78, 0xf0}} {
+		framer := NewFramer()
+		frames, discards := framer.Feed(append(append([]byte{}, header...), stream...))
+		assert.Len(t, frames, 100, "%x", header)
+		assert.NotEmpty(t, discards, "%x", header)
+		assert.Equal(t, len(header), framer.Discarded(), "%x", header)
+		assert.Zero(t, framer.Buffered())
+	}
+
+	// One packet after the bogus header is enough to resynchronise, even when it arrives in pieces.
+	framer := NewFramer()
+	frames, _ := framer.Feed(append([]byte{0x79, 0x79, 0x01, 0x00}, login[:10]...))
+	assert.Empty(t, frames)
+	frames, _ = framer.Feed(login[10:])
+	require.Len(t, frames, 1)
+	assert.Equal(t, login, frames[0])
+}
+
+func TestFramerFlush(t *testing.T) {
+	login := buildPacket(t, ParserLogin, "0868120145233604", 1)
+
+	framer := NewFramer()
+	frames, _ := framer.Feed(append([]byte{0x78, 0x78, 0x40}, login[:12]...))
+	assert.Empty(t, frames)
+
+	frames, discards := framer.Flush()
+	assert.Empty(t, frames)
+	assert.NotEmpty(t, discards)
+	assert.Zero(t, framer.Buffered())
+	assert.Equal(t, 15, framer.Discarded())
+
+	frames, discards = framer.Flush()
+	assert.Empty(t, frames)
+	assert.Empty(t, discards)
+}
@@ -0,0 +1,87 @@
+package concox
+
+import (
+	"fmt"
+
+	"github.com/xen0tic/utils/generics"
+)
+
+var terminalAlarms = []string{
+	"Normal",
+	"Vibration",
+	"Power Cut",
+	"Low Battery",
+	"SOS",
+}
+
+var voltageLevels = []string{
+	"No Power",
+	"Extremely Low Battery",
+	"Very Low Battery",
+	"Low Battery",
+	"Medium",
+	"High",
+	"Extremely High",
+}
+
+var gsmSignals = []string{
+	"No Signal",
+	"Extremely Weak",
+	"Very Weak",
+	"Good",
+	"Strong",
+}
+
+// DecodeTerminalInfo maps the terminal information bitfield sent in status and alarm packets.
+//
+//	bit 7    oil and electricity disconnected
+//	bit 6    GPS tracking on
+//	bit 5-3  alarm: 100 SOS, 011 low battery, 010 power cut, 001 vibration, 000 normal
+//	bit 2    charging
+//	bit 1    ACC high
+//	bit 0    defense activated
+func DecodeTerminalInfo(info byte) generics.HeartBeatTerminalInfo {
+	alarm := int(info>>3) & 0x07
+	return generics.HeartBeatTerminalInfo{
+		Status:      info&0x01 != 0,
+		Ignition:    info&0x02 != 0,
+		Charging:    info&0x04 != 0,
+		Alarm:       generics.StringNumber{String: lookupString(terminalAlarms, alarm), Number: alarm},
+		GpsTracking: info&0x40 != 0,
+		RelayState:  info&0x80 != 0,
+	}
+}
+
+// DecodeVoltageLevel maps a voltage level byte, 0 (no power) to 6 (extremely high).
+func DecodeVoltageLevel(level byte) generics.StringNumber {
+	return generics.StringNumber{String: lookupString(voltageLevels, int(level)), Number: int(level)}
+}
+
+// DecodeGsmSignal maps a GSM signal strength byte, 0 (no signal) to 4 (strong).
+func DecodeGsmSignal(signal byte) generics.StringNumber {
+	return generics.StringNumber{String: lookupString(gsmSignals, int(signal)), Number: int(signal)}
+}
+
+// DecodeHeartBeat decodes a status packet (0x13).
+func DecodeHeartBeat(input []byte) (generics.HeartBeat, error) {
+	var heartBeat generics.HeartBeat
+
+	_, content, err := packetContent(input, 3, ParserStatus)
+	if err != nil {
+		return heartBeat, err
+	}
+
+	heartBeat.TerminalInfo = DecodeTerminalInfo(content[0])
+	heartBeat.Voltage = DecodeVoltageLevel(content[1])
+	heartBeat.GsmSignal = DecodeGsmSignal(content[2])
+	heartBeat.SerialNumber = fmt.Sprintf("%04x", GetPackageSn(input))
+
+	return heartBeat, nil
+}
+
+func lookupString(names []string, index int) string {
+	if index < 0 || index >= len(names) {
+		return "Unknown"
+	}
+	return names[index]
+}
@@ -0,0 +1,33 @@
+package concox
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeHeartBeat(t *testing.T) {
+	packet := buildPacket(t, ParserStatus, "c606000002", 0x1f)
+	require.True(t, ValidatePackage(packet))
+
+	heartBeat, err := DecodeHeartBeat(packet)
+	require.NoError(t, err)
+	assert.True(t, heartBeat.TerminalInfo.RelayState)
+	assert.True(t, heartBeat.TerminalInfo.GpsTracking)
+	assert.True(t, heartBeat.TerminalInfo.Charging)
+	assert.True(t, heartBeat.TerminalInfo.Ignition)
+	assert.False(t, heartBeat.TerminalInfo.Status)
+	assert.Equal(t, "Normal", heartBeat.TerminalInfo.Alarm.String)
+	assert.Equal(t, "Extremely High", heartBeat.Voltage.String)
+	assert.Equal(t, 6, heartBeat.Voltage.Number)
+	assert.Equal(t, "No Signal", heartBeat.GsmSignal.String)
+	assert.Equal(t, "001f", heartBeat.SerialNumber)
+
+	info := DecodeTerminalInfo(0x19)
+	assert.Equal(t, "Low Battery", info.Alarm.String)
+	assert.Equal(t, 3, info.Alarm.Number)
+	assert.True(t, info.Status)
+
+	assert.Equal(t, "Unknown", DecodeVoltageLevel(9).String)
+}
@@ -0,0 +1,182 @@
+package concox
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	InformationExternalVoltage    = 0x00
+	InformationTerminalStatusSync = 0x04
+	InformationDoorStatus         = 0x05
+	InformationSelfCheck          = 0x08
+	InformationVisibleSatellites  = 0x09
+	InformationIccid              = 0x0a
+)
+
+// InformationContent is the typed payload of an information transmission packet (0x94).
+type InformationContent interface {
+	InformationType() byte
+	String() string
+}
+
+// ExternalVoltage is the external power voltage in volts.
+type ExternalVoltage float64
+
+// TerminalStatusSync is the "KEY=VALUE;" status text the terminal synchronises, e.g. "ALM1=FF;STA1=CC;".
+type TerminalStatusSync string
+
+type DoorStatus struct {
+	Open        bool `json:"open"`
+	TriggerHigh bool `json:"triggerHigh"`
+	IoHigh      bool `json:"ioHigh"`
+}
+
+// SelfCheck is the self-check parameter block, kept as sent since its layout differs between firmwares.
+type SelfCheck []byte
+
+// VisibleSatellites holds the signal-to-noise ratio of each satellite in view.
+type VisibleSatellites []int
+
+type SimInformation struct {
+	Imei  string `json:"imei"`
+	Imsi  string `json:"imsi"`
+	Iccid string `json:"iccid"`
+}
+
+// RawInformation is returned for information types this package does not know.
+type RawInformation struct {
+	Type byte
+	Data []byte
+}
+
+func (v ExternalVoltage) InformationType() byte { return InformationExternalVoltage }
+
+func (v ExternalVoltage) String() string {
+	return strconv.FormatFloat(float64(v), 'f', 2, 64)
+}
+
+func (s TerminalStatusSync) InformationType() byte { return InformationTerminalStatusSync }
+
+func (s TerminalStatusSync) String() string {
+	return string(s)
+}
+
+// Fields splits the status text into its key/value pairs.
+func (s TerminalStatusSync) Fields() map[string]string {
+	fields := make(map[string]string)
+	for _, item := range strings.Split(string(s), ";") {
+		if key, value, ok := strings.Cut(item, "="); ok {
+			fields[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+	}
+	return fields
+}
+
+func (d DoorStatus) InformationType() byte { return InformationDoorStatus }
+
+func (d DoorStatus) String() string {
+	return fmt.Sprintf("open=%t;trigger_high=%t;io_high=%t", d.Open, d.TriggerHigh, d.IoHigh)
+}
+
+func (s SelfCheck) InformationType() byte { return InformationSelfCheck }
+
+func (s SelfCheck) String() string {
+	return hex.EncodeToString(s)
+}
+
+func (v VisibleSatellites) InformationType() byte { return InformationVisibleSatellites }
+
+func (v VisibleSatellites) String() string {
+	snr := make([]string, 0, len(v))
+	for _, item := range v {
+		snr = append(snr, strconv.Itoa(item))
+	}
+	return strings.Join(snr, ",")
+}
+
+func (s SimInformation) InformationType() byte { return InformationIccid }
+
+func (s SimInformation) String() string {
+	return fmt.Sprintf("imei=%s;imsi=%s;iccid=%s", s.Imei, s.Imsi, s.Iccid)
+}
+
+func (r RawInformation) InformationType() byte { return r.Type }
+
+func (r RawInformation) String() string {
+	return hex.EncodeToString(r.Data)
+}
+
+// DecodeInformation decodes an information transmission packet (0x94) according to its information type.
+func DecodeInformation(input []byte) (InformationContent, error) {
+	_, content, err := packetContent(input, 1, ParserInformation)
+	if err != nil {
+		return nil, err
+	}
+
+	infoType, data := content[0], content[1:]
+	switch infoType {
+	case InformationExternalVoltage:
+		if len(data) < 2 {
+			return nil, ErrShortPacket
+		}
+		return ExternalVoltage(float64(binary.BigEndian.Uint16(data)) / 100), nil
+	case InformationTerminalStatusSync:
+		return TerminalStatusSync(data), nil
+	case InformationDoorStatus:
+		if len(data) < 1 {
+			return nil, ErrShortPacket
+		}
+		return DoorStatus{
+			Open:        data[0]&0x01 != 0,
+			TriggerHigh: data[0]&0x02 != 0,
+			IoHigh:      data[0]&0x04 != 0,
+		}, nil
+	case InformationSelfCheck:
+		return SelfCheck(append([]byte(nil), data...)), nil
+	case InformationVisibleSatellites:
+		if len(data) < 1 || len(data) < 1+int(data[0]) {
+			return nil, ErrShortPacket
+		}
+		satellites := make(VisibleSatellites, 0, data[0])
+		for _, snr := range data[1 : 1+int(data[0])] {
+			satellites = append(satellites, int(snr))
+		}
+		return satellites, nil
+	case InformationIccid:
+		if len(data) < 26 {
+			return nil, ErrShortPacket
+		}
+		return SimInformation{
+			Imei:  strings.TrimPrefix(hex.EncodeToString(data[0:8]), "0"),
+			Imsi:  strings.TrimPrefix(hex.EncodeToString(data[8:16]), "0"),
+			Iccid: hex.EncodeToString(data[16:26]),
+		}, nil
+	}
+
+	return RawInformation{Type: infoType, Data: append([]byte(nil), data...)}, nil
+}
+
+// InformationRecord renders information as the (informationType, content) pair stored by
+// mysql.InsertDeviceInformation. The type is the two digit hex information type byte. That query is built
+// with fmt.Sprintf, so content the device controls is stored as "hex:" and its hex encoding unless it is
+// printable ASCII without quotes or backslashes.
+func InformationRecord(info InformationContent) (string, string) {
+	content := info.String()
+	if !recordSafe(content) {
+		content = "hex:" + hex.EncodeToString([]byte(content))
+	}
+	return fmt.Sprintf("%02x", info.InformationType()), content
+}
+
+func recordSafe(content string) bool {
+	for i := 0; i < len(content); i++ {
+		if c := content[i]; c < 0x20 || c > 0x7e || c == '\'' || c == '"' || c == '\\' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,50 @@
+package concox
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeInformation(t *testing.T) {
+	packet := buildLongPacket(t, ParserInformation, "0004d2", 0x30)
+	require.True(t, ValidatePackage(packet))
+
+	info, err := DecodeInformation(packet)
+	require.NoError(t, err)
+	assert.Equal(t, ExternalVoltage(12.34), info)
+	infoType, content := InformationRecord(info)
+	assert.Equal(t, "00", infoType)
+	assert.Equal(t, "12.34", content)
+
+	status := hex.EncodeToString([]byte("ALM1=FF;STA1=CC;SOS=,,;"))
+	info, err = DecodeInformation(buildLongPacket(t, ParserInformation, "04"+status, 0x31))
+	require.NoError(t, err)
+	require.IsType(t, TerminalStatusSync(""), info)
+	assert.Equal(t, "CC", info.(TerminalStatusSync).Fields()["STA1"])
+	_, content = InformationRecord(info)
+	assert.Equal(t, "ALM1=FF;STA1=CC;SOS=,,;", content)
+
+	_, content = InformationRecord(TerminalStatusSync("STA1=');DROP TABLE devices;--"))
+	assert.Equal(t, "hex:"+hex.EncodeToString([]byte("STA1=');DROP TABLE devices;--")), content)
+
+	info, err = DecodeInformation(buildLongPacket(t, ParserInformation, "0505", 0x32))
+	require.NoError(t, err)
+	assert.Equal(t, DoorStatus{Open: true, IoHigh: true}, info)
+
+	sim := "0868120145233604" + "0460001234567890" + "89860012345678901234"
+	info, err = DecodeInformation(buildLongPacket(t, ParserInformation, "0a"+sim, 0x33))
+	require.NoError(t, err)
+	assert.Equal(t, SimInformation{Imei: "868120145233604", Imsi: "460001234567890", Iccid: "89860012345678901234"}, info)
+
+	info, err = DecodeInformation(buildLongPacket(t, ParserInformation, "7f0102", 0x34))
+	require.NoError(t, err)
+	infoType, content = InformationRecord(info)
+	assert.Equal(t, "7f", infoType)
+	assert.Equal(t, "0102", content)
+
+	_, err = DecodeInformation(buildLongPacket(t, ParserInformation, "00", 0x35))
+	assert.ErrorIs(t, err, ErrShortPacket)
+}
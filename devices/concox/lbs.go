@@ -0,0 +1,84 @@
+package concox
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/xen0tic/utils/generics"
+)
+
+const (
+	lbsNeighbourCount  = 6
+	lbsNeighbourLength = 6
+)
+
+// DecodeLbsLocation decodes a GT06 (0x18) or X3 (0x28) multi-cell LBS packet into the main cell, six
+// neighbour cells, the time advance and the language.
+func DecodeLbsLocation(input []byte) (generics.LbsLocation, error) {
+	var lbs generics.LbsLocation
+
+	_, content, err := packetContent(input, dateTimeLength, ParserLBSLocationGT06, ParserLBSLocationX3)
+	if err != nil {
+		return lbs, err
+	}
+
+	offset, err := decodeLbsCells(content[dateTimeLength:], &lbs)
+	if err != nil {
+		return lbs, err
+	}
+	offset += dateTimeLength
+
+	// The language field is two bytes, the second one carries the language.
+	if len(content) >= offset+2 {
+		lbs.Language = LanguageString(content[offset+1])
+	}
+
+	lbs.Date = decodeDateTime(content).Format(generics.DateFormat)
+	lbs.SerialNumber = fmt.Sprintf("%04x", GetPackageSn(input))
+
+	return lbs, nil
+}
+
+// decodeLbsCells reads the main cell, the six neighbour cells and the time advance and returns the number
+// of bytes consumed.
+func decodeLbsCells(content []byte, lbs *generics.LbsLocation) (int, error) {
+	var cell generics.Location
+	offset, ok := decodeCell(content, &cell)
+	if !ok {
+		return 0, ErrShortPacket
+	}
+
+	if len(content) < offset+1+lbsNeighbourCount*lbsNeighbourLength+1 {
+		return 0, ErrShortPacket
+	}
+
+	lbs.MCC = cell.Mcc
+	lbs.MNC = cell.Mnc
+	lbs.LAC = cell.Lac
+	lbs.CellId = cell.CellId
+	lbs.RSSI = int(content[offset])
+	offset++
+
+	neighbours := [lbsNeighbourCount]struct {
+		lac    *uint16
+		cellId *int64
+		rssi   *int
+	}{
+		{&lbs.NLAC1, &lbs.NCellId1, &lbs.NRSSI1},
+		{&lbs.NLAC2, &lbs.NCellId2, &lbs.NRSSI2},
+		{&lbs.NLAC3, &lbs.NCellId3, &lbs.NRSSI3},
+		{&lbs.NLAC4, &lbs.NCellId4, &lbs.NRSSI4},
+		{&lbs.NLAC5, &lbs.NCellId5, &lbs.NRSSI5},
+		{&lbs.NLAC6, &lbs.NCellId6, &lbs.NRSSI6},
+	}
+	for _, neighbour := range neighbours {
+		*neighbour.lac = binary.BigEndian.Uint16(content[offset : offset+2])
+		*neighbour.cellId = int64(decodeUint24(content[offset+2 : offset+5]))
+		*neighbour.rssi = int(content[offset+5])
+		offset += lbsNeighbourLength
+	}
+
+	lbs.TimeAdvance = int(content[offset])
+
+	return offset + 1, nil
+}
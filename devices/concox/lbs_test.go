@@ -0,0 +1,35 @@
+package concox
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeLbsLocation(t *testing.T) {
+	neighbours := "287e001fb93c" + "287f001fba3b" + "2880001fbb3a" + "2881001fbc39" + "2882001fbd38" + "2883001fbe37"
+	packet := buildPacket(t, ParserLBSLocationX3, "0b081d112e1081cc0001287d001fb83d"+neighbours+"ff0002", 0x20)
+	require.True(t, ValidatePackage(packet))
+
+	lbs, err := DecodeLbsLocation(packet)
+	require.NoError(t, err)
+	assert.Equal(t, uint16(460), lbs.MCC)
+	assert.Equal(t, uint(1), lbs.MNC)
+	assert.Equal(t, uint16(0x287d), lbs.LAC)
+	assert.Equal(t, int64(0x1fb8), lbs.CellId)
+	assert.Equal(t, 0x3d, lbs.RSSI)
+	assert.Equal(t, uint16(0x287e), lbs.NLAC1)
+	assert.Equal(t, int64(0x1fb9), lbs.NCellId1)
+	assert.Equal(t, 0x3c, lbs.NRSSI1)
+	assert.Equal(t, uint16(0x2883), lbs.NLAC6)
+	assert.Equal(t, int64(0x1fbe), lbs.NCellId6)
+	assert.Equal(t, 0x37, lbs.NRSSI6)
+	assert.Equal(t, 0xff, lbs.TimeAdvance)
+	assert.Equal(t, "English", lbs.Language)
+	assert.Equal(t, "2011-08-29 17:46:16", lbs.Date)
+	assert.Equal(t, "0020", lbs.SerialNumber)
+
+	_, err = DecodeLbsLocation(packet[:len(packet)-12])
+	assert.Error(t, err)
+}
@@ -0,0 +1,225 @@
+package concox
+
+import (
+	"encoding/binary"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/xen0tic/utils/generics"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	dateTimeLength = 6
+	gpsBlockLength = 12
+	lbsBlockLength = 8
+
+	// locationGT06Length is the content length of a GT06 location packet without the optional ACC byte.
+	locationGT06Length = dateTimeLength + gpsBlockLength + lbsBlockLength
+)
+
+const (
+	UploadModeInterval         = 0x00
+	UploadModeDistance         = 0x01
+	UploadModeInflection       = 0x02
+	UploadModeAccStatus        = 0x03
+	UploadModeStaticLastPoint  = 0x04
+	UploadModeNetworkRecovered = 0x05
+	UploadModeEphemeris        = 0x06
+	UploadModeSideKey          = 0x07
+	UploadModePowerOn          = 0x08
+	UploadModeStaticUpdate     = 0x0a
+	UploadModeStaticLastLngLat = 0x0d
+	UploadModeGpsDup           = 0x0e
+	UploadModeExitTracking     = 0x0f
+)
+
+var uploadModes = map[byte]string{
+	UploadModeInterval:         "Time Interval",
+	UploadModeDistance:         "Distance Interval",
+	UploadModeInflection:       "Inflection Point",
+	UploadModeAccStatus:        "ACC Status",
+	UploadModeStaticLastPoint:  "Last Point Before Static",
+	UploadModeNetworkRecovered: "Network Recovered",
+	UploadModeEphemeris:        "Ephemeris Update",
+	UploadModeSideKey:          "Side Key",
+	UploadModePowerOn:          "Power On",
+	UploadModeStaticUpdate:     "Static Update",
+	UploadModeStaticLastLngLat: "Last Position After Static",
+	UploadModeGpsDup:           "GPS Dup",
+	UploadModeExitTracking:     "Exit Tracking",
+}
+
+// LocationX3 is an X3 location packet (0x22). ReUpload is set for points the device buffered while it
+// was offline, as opposed to real-time points.
+type LocationX3 struct {
+	generics.Location
+	UploadMode    byte   `json:"uploadMode" bson:"uploadMode"`
+	UploadModeStr string `json:"uploadModeStr" bson:"uploadModeStr"`
+	ReUpload      bool   `json:"reUpload" bson:"reUpload"`
+	Mileage       uint32 `json:"mileage" bson:"mileage"`
+}
+
+// GetPackageContent returns the information content of a packet, which sits between the protocol number
+// and the serial number.
+func GetPackageContent(input []byte) []byte {
+	if len(input) < 2 {
+		return nil
+	}
+
+	start := 4
+	if IsLongPackage(input) {
+		start = 5
+	}
+
+	if len(input) < start+6 {
+		return nil
+	}
+	return input[start : len(input)-6]
+}
+
+// packetContent checks that input is a packet of one of protocols with at least minContent bytes of
+// content and returns its protocol number and content.
+func packetContent(input []byte, minContent int, protocols ...byte) (byte, []byte, error) {
+	if len(input) < minFrameLength {
+		return 0, nil, ErrShortPacket
+	}
+
+	protocol := GetPackageType(input)
+	if !slices.Contains(protocols, protocol) {
+		return protocol, nil, ErrUnexpectedProtocol
+	}
+
+	content := GetPackageContent(input)
+	if len(content) < minContent {
+		return protocol, nil, ErrShortPacket
+	}
+	return protocol, content, nil
+}
+
+// GetPackageSn returns the serial number of a packet, read from the two bytes before the CRC.
+func GetPackageSn(input []byte) uint16 {
+	if len(input) < 10 {
+		return 0
+	}
+	return binary.BigEndian.Uint16(input[len(input)-6 : len(input)-4])
+}
+
+// DecodeCoordinate converts a raw latitude or longitude value into degrees.
+func DecodeCoordinate(value float64) float64 {
+	return math.Round(value/60.0/30000.0*1e6) / 1e6
+}
+
+// DecodeLocation decodes the location shared by GT06 (0x12) and X3 (0x22) location packets.
+func DecodeLocation(input []byte) (generics.Location, error) {
+	location, _, err := decodeLocation(input, ParserLocationGT06, ParserLocationX3)
+	return location, err
+}
+
+// DecodeLocationX3 decodes an X3 location packet (0x22) including the fields GT06 packets lack.
+func DecodeLocationX3(input []byte) (LocationX3, error) {
+	var result LocationX3
+
+	location, extra, err := decodeLocation(input, ParserLocationX3)
+	if err != nil {
+		return result, err
+	}
+	if len(extra) < 3 {
+		return result, ErrShortPacket
+	}
+
+	result.Location = location
+	result.UploadMode = extra[1]
+	result.UploadModeStr = UploadModeString(extra[1])
+	result.ReUpload = extra[2] == 0x01
+	if len(extra) >= 7 {
+		result.Mileage = binary.BigEndian.Uint32(extra[3:7])
+	}
+
+	return result, nil
+}
+
+// UploadModeString returns the name of a data upload mode.
+func UploadModeString(mode byte) string {
+	if name, ok := uploadModes[mode]; ok {
+		return name
+	}
+	return "Unknown"
+}
+
+func decodeLocation(input []byte, protocols ...byte) (generics.Location, []byte, error) {
+	var location generics.Location
+
+	_, content, err := packetContent(input, locationGT06Length, protocols...)
+	if err != nil {
+		return location, nil, err
+	}
+
+	location.Timestamp = decodeDateTime(content)
+	location.Date = location.Timestamp.Format(generics.DateFormat)
+	location.Nanoseconds = location.Timestamp.UnixNano()
+	decodeGpsBlock(content[dateTimeLength:], &location)
+
+	offset := dateTimeLength + gpsBlockLength
+	n, ok := decodeCell(content[offset:], &location)
+	if !ok {
+		return location, nil, ErrShortPacket
+	}
+
+	extra := content[offset+n:]
+	if len(extra) > 0 {
+		location.AccOff = extra[0] == 0x00
+	}
+
+	location.SerialNumber = GetPackageSn(input)
+
+	return location, extra, nil
+}
+
+func decodeDateTime(input []byte) time.Time {
+	return time.Date(2000+int(input[0]), time.Month(input[1]), int(input[2]),
+		int(input[3]), int(input[4]), int(input[5]), 0, time.UTC)
+}
+
+func decodeGpsBlock(input []byte, location *generics.Location) {
+	status := DecodeCourseStatus(binary.BigEndian.Uint16(input[10:12]))
+	latitude, longitude := status.Coordinates(binary.BigEndian.Uint32(input[1:5]), binary.BigEndian.Uint32(input[5:9]))
+
+	location.Gps = strconv.Itoa(DecodeGpsInfo(input[0]).Satellites)
+	location.Lat = strconv.FormatFloat(latitude, 'f', 6, 64)
+	location.Lng = strconv.FormatFloat(longitude, 'f', 6, 64)
+	location.Speed = strconv.Itoa(int(input[9]))
+	location.Course = strconv.Itoa(status.Course)
+}
+
+// decodeCell reads MCC, MNC, LAC and cell ID and returns the number of bytes consumed. The MNC takes two
+// bytes when the highest bit of the MCC is set.
+func decodeCell(input []byte, location *generics.Location) (int, bool) {
+	if len(input) < lbsBlockLength {
+		return 0, false
+	}
+
+	mcc := binary.BigEndian.Uint16(input[0:2])
+	location.Mcc = mcc & 0x7fff
+
+	offset := 3
+	if mcc&0x8000 != 0 {
+		if len(input) < lbsBlockLength+1 {
+			return 0, false
+		}
+		location.Mnc = uint(binary.BigEndian.Uint16(input[2:4]))
+		offset = 4
+	} else {
+		location.Mnc = uint(input[2])
+	}
+
+	location.Lac = binary.BigEndian.Uint16(input[offset : offset+2])
+	location.CellId = int64(decodeUint24(input[offset+2 : offset+5]))
+
+	return offset + 5, true
+}
+
+func decodeUint24(input []byte) uint32 {
+	return uint32(input[0])<<16 | uint32(input[1])<<8 | uint32(input[2])
+}
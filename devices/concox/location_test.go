@@ -0,0 +1,62 @@
+package concox
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeLocation(t *testing.T) {
+	packet := buildPacket(t, ParserLocationGT06, "0b081d112e10cc027ac7eb0c46584900148f01cc00287d001fb8", 3)
+	require.True(t, ValidatePackage(packet))
+
+	location, err := DecodeLocation(packet)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2011, 8, 29, 17, 46, 16, 0, time.UTC), location.Timestamp)
+	assert.Equal(t, "2011-08-29 17:46:16", location.Date)
+	assert.Equal(t, "12", location.Gps)
+	assert.Equal(t, "23.111668", location.Lat)
+	assert.Equal(t, "114.409285", location.Lng)
+	assert.Equal(t, "0", location.Speed)
+	assert.Equal(t, "143", location.Course)
+	assert.Equal(t, uint16(460), location.Mcc)
+	assert.Equal(t, uint(0), location.Mnc)
+	assert.Equal(t, uint16(0x287d), location.Lac)
+	assert.Equal(t, int64(0x1fb8), location.CellId)
+	assert.Equal(t, uint16(3), location.SerialNumber)
+	assert.False(t, location.AccOff)
+
+	southWest := buildPacket(t, ParserLocationGT06, "0b081d112e10cc027ac7eb0c46584900188f01cc00287d001fb800", 4)
+	location, err = DecodeLocation(southWest)
+	require.NoError(t, err)
+	assert.Equal(t, "-23.111668", location.Lat)
+	assert.Equal(t, "-114.409285", location.Lng)
+	assert.True(t, location.AccOff)
+
+	_, err = DecodeLocation(packet[:12])
+	assert.ErrorIs(t, err, ErrShortPacket)
+	_, err = DecodeLocation(buildPacket(t, ParserStatus, "4004040001", 5))
+	assert.ErrorIs(t, err, ErrUnexpectedProtocol)
+}
+
+func TestDecodeLocationX3(t *testing.T) {
+	packet := buildPacket(t, ParserLocationX3, "0b081d112e10cc027ac7eb0c46584900148f81cc0001287d001fb801050100001234", 7)
+
+	location, err := DecodeLocationX3(packet)
+	require.NoError(t, err)
+	assert.Equal(t, uint16(460), location.Mcc)
+	assert.Equal(t, uint(1), location.Mnc)
+	assert.Equal(t, uint16(0x287d), location.Lac)
+	assert.Equal(t, int64(0x1fb8), location.CellId)
+	assert.False(t, location.AccOff)
+	assert.Equal(t, byte(UploadModeNetworkRecovered), location.UploadMode)
+	assert.Equal(t, "Network Recovered", location.UploadModeStr)
+	assert.True(t, location.ReUpload)
+	assert.Equal(t, uint32(0x1234), location.Mileage)
+	assert.Equal(t, uint16(7), location.SerialNumber)
+
+	_, err = DecodeLocationX3(buildPacket(t, ParserLocationGT06, "0b081d112e10cc027ac7eb0c46584900148f01cc00287d001fb8", 3))
+	assert.ErrorIs(t, err, ErrUnexpectedProtocol)
+}
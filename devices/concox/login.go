@@ -0,0 +1,79 @@
+package concox
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/xen0tic/utils/generics"
+)
+
+const loginImeiLength = 8
+
+// Login is a login packet (0x01). Older firmware only sends the IMEI, so ModelCode, TimeZoneOffset and
+// Language are zero when the packet does not carry them. ImeiValid is false when the IMEI fails its Luhn
+// check, which some devices in the field do, and the gateway decides whether to accept them.
+type Login struct {
+	Imei           generics.Imei `json:"imei"`
+	ImeiValid      bool          `json:"imeiValid"`
+	ModelCode      uint16        `json:"modelCode"`
+	TimeZoneOffset time.Duration `json:"timeZoneOffset"`
+	Language       byte          `json:"language"`
+	SerialNumber   uint16        `json:"serialNumber"`
+}
+
+// Location returns the time zone of the device as a fixed zone.
+func (l Login) Location() *time.Location {
+	sign := "+"
+	offset := l.TimeZoneOffset
+	if offset < 0 {
+		sign = "-"
+		offset = -offset
+	}
+	name := fmt.Sprintf("GMT%s%02d:%02d", sign, int(offset.Hours()), int(offset.Minutes())%60)
+	return time.FixedZone(name, int(l.TimeZoneOffset.Seconds()))
+}
+
+// DecodeLogin decodes a login packet: the IMEI, then the optional type identification code and the
+// optional time zone/language word.
+//
+//	bit 15-4  time zone as hhmm, e.g. 800 for GMT+8:00 and 530 for GMT+5:30
+//	bit 3     0 east, 1 west
+//	bit 1-0   language, 01 Chinese, 10 English
+func DecodeLogin(input []byte) (Login, error) {
+	var login Login
+
+	_, content, err := packetContent(input, loginImeiLength, ParserLogin)
+	if err != nil {
+		return login, err
+	}
+
+	imei, err := generics.DecodeIMEIBCD(content[:loginImeiLength])
+	if err != nil && !errors.Is(err, generics.ErrImeiCheckDigit) {
+		return login, fmt.Errorf("concox: login: %w", err)
+	}
+	login.Imei = imei
+	login.ImeiValid = err == nil
+	login.SerialNumber = GetPackageSn(input)
+
+	if len(content) >= loginImeiLength+2 {
+		login.ModelCode = binary.BigEndian.Uint16(content[loginImeiLength : loginImeiLength+2])
+	}
+
+	if len(content) >= loginImeiLength+4 {
+		zone := binary.BigEndian.Uint16(content[loginImeiLength+2 : loginImeiLength+4])
+		login.TimeZoneOffset, login.Language = decodeTimeZone(zone)
+	}
+
+	return login, nil
+}
+
+func decodeTimeZone(zone uint16) (time.Duration, byte) {
+	value := int(zone >> 4)
+	offset := time.Duration(value/100)*time.Hour + time.Duration(value%100)*time.Minute
+	if zone&0x08 != 0 {
+		offset = -offset
+	}
+	return offset, byte(zone & 0x03)
+}
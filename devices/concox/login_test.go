@@ -0,0 +1,54 @@
+package concox
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xen0tic/utils/generics"
+)
+
+func TestDecodeLogin(t *testing.T) {
+	login, err := DecodeLogin(buildPacket(t, ParserLogin, "0868120145233604", 1))
+	require.NoError(t, err)
+	assert.Equal(t, generics.Imei("868120145233604"), login.Imei)
+	assert.Zero(t, login.ModelCode)
+	assert.Zero(t, login.TimeZoneOffset)
+
+	assert.True(t, login.ImeiValid)
+
+	login, err = DecodeLogin(buildPacket(t, ParserLogin, "0868120145233605", 1))
+	require.NoError(t, err)
+	assert.Equal(t, generics.Imei("868120145233605"), login.Imei)
+	assert.False(t, login.ImeiValid)
+
+	_, err = DecodeLogin(buildPacket(t, ParserLogin, "1868120145233604", 1))
+	assert.ErrorIs(t, err, generics.ErrImeiBCD)
+
+	// 330 << 4 is 0x14a0: GMT+3:30, east, English.
+	login, err = DecodeLogin(buildPacket(t, ParserLogin, "0868120145233604"+"3604"+"14a2", 2))
+	require.NoError(t, err)
+	assert.Equal(t, uint16(0x3604), login.ModelCode)
+	assert.Equal(t, 3*time.Hour+30*time.Minute, login.TimeZoneOffset)
+	assert.Equal(t, byte(LanguageEnglish), login.Language)
+	assert.Equal(t, "GMT+03:30", login.Location().String())
+	assert.Equal(t, uint16(2), login.SerialNumber)
+
+	login, err = DecodeLogin(buildPacket(t, ParserLogin, "0868120145233604"+"3604"+"1f49", 3))
+	require.NoError(t, err)
+	assert.Equal(t, -5*time.Hour, login.TimeZoneOffset)
+	assert.Equal(t, byte(LanguageChinese), login.Language)
+}
+
+func TestLoginRoundTrip(t *testing.T) {
+	login, err := DecodeLogin(buildPacket(t, ParserLogin, "0868120145233605", 1))
+	require.NoError(t, err)
+
+	data, err := json.Marshal(login)
+	require.NoError(t, err)
+	var decoded Login
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, login, decoded)
+}
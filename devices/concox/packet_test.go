@@ -0,0 +1,35 @@
+package concox
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func buildPacket(t *testing.T, protocol byte, content string, sn uint16) []byte {
+	t.Helper()
+
+	body, err := hex.DecodeString(content)
+	require.NoError(t, err)
+
+	packet := []byte{ParserStartBit, ParserStartBit, byte(len(body) + 5), protocol}
+	packet = append(packet, body...)
+	packet = append(packet, byte(sn>>8), byte(sn))
+	crc1, crc2 := GenerateCrc(packet[2:])
+	return append(packet, crc1, crc2, ParserEndBitFirst, ParserEndBitEnd)
+}
+
+func buildLongPacket(t *testing.T, protocol byte, content string, sn uint16) []byte {
+	t.Helper()
+
+	body, err := hex.DecodeString(content)
+	require.NoError(t, err)
+
+	length := len(body) + 5
+	packet := []byte{ParserLongStartBit, ParserLongStartBit, byte(length >> 8), byte(length), protocol}
+	packet = append(packet, body...)
+	packet = append(packet, byte(sn>>8), byte(sn))
+	crc1, crc2 := GenerateCrc(packet[2:])
+	return append(packet, crc1, crc2, ParserEndBitFirst, ParserEndBitEnd)
+}
@@ -0,0 +1,80 @@
+package concox
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrNoResponse occurs when BuildResponse is given a packet the server must not acknowledge.
+var ErrNoResponse = errors.New("concox: protocol number does not need a response")
+
+// Clock returns the current time. It is injected into a Responder so time calibration responses can be
+// made deterministic.
+type Clock func() time.Time
+
+type Responder struct {
+	clock Clock
+}
+
+var defaultResponder = NewResponder(time.Now)
+
+func NewResponder(clock Clock) *Responder {
+	if clock == nil {
+		clock = time.Now
+	}
+	return &Responder{clock: clock}
+}
+
+// BuildResponse builds the acknowledgement for a device packet using the system clock.
+func BuildResponse(input []byte) ([]byte, error) {
+	return defaultResponder.BuildResponse(input)
+}
+
+// BuildResponse builds the acknowledgement for a device packet. The response echoes the protocol number
+// and serial number of the request and uses the same short (0x78) or long (0x79) framing.
+func (r *Responder) BuildResponse(input []byte) ([]byte, error) {
+	if len(input) < 10 {
+		return nil, ErrShortPacket
+	}
+
+	var content []byte
+	switch GetPackageType(input) {
+	case ParserLogin, ParserStatus, ParserAlarmGT06, ParserAlarmX3, ParserAlarmX3V2, ParserInformation:
+	case ParserTimeCalibration:
+		content = BuildTimeCalibrationBody(r.clock())
+	default:
+		return nil, ErrNoResponse
+	}
+
+	return EncodePackage(IsLongPackage(input), GetPackageType(input), content, GetPackageSn(input)), nil
+}
+
+// EncodePackage frames content as a packet with the given protocol and serial number and computes its CRC.
+func EncodePackage(long bool, protocol byte, content []byte, sn uint16) []byte {
+	return AppendPackage(make([]byte, 0, len(content)+11), long, protocol, content, sn)
+}
+
+// AppendPackage appends the encoded packet to dst, so a caller reusing dst encodes without allocating.
+func AppendPackage(dst []byte, long bool, protocol byte, content []byte, sn uint16) []byte {
+	length := len(content) + 5
+
+	start := len(dst)
+	if long {
+		dst = append(dst, ParserLongStartBit, ParserLongStartBit, byte(length>>8), byte(length))
+	} else {
+		dst = append(dst, ParserStartBit, ParserStartBit, byte(length))
+	}
+	dst = append(dst, protocol)
+	dst = append(dst, content...)
+	dst = append(dst, byte(sn>>8), byte(sn))
+
+	crc1, crc2 := GenerateCrc(dst[start+2:])
+
+	return append(dst, crc1, crc2, ParserEndBitFirst, ParserEndBitEnd)
+}
+
+// BuildTimeCalibrationBody returns the body of a time calibration response (0x8a): YY MM DD hh mm ss in UTC.
+func BuildTimeCalibrationBody(t time.Time) []byte {
+	t = t.UTC()
+	return []byte{byte(t.Year() - 2000), byte(t.Month()), byte(t.Day()), byte(t.Hour()), byte(t.Minute()), byte(t.Second())}
+}
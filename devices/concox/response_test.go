@@ -0,0 +1,91 @@
+package concox
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildResponse(t *testing.T) {
+	login := buildPacket(t, ParserLogin, "0123456789012345", 1)
+
+	response, err := BuildResponse(login)
+	require.NoError(t, err)
+	assert.Equal(t, "787805010001d9dc0d0a", hex.EncodeToString(response))
+
+	response, err = BuildResponse(buildPacket(t, ParserAlarmX3, "00", 0x0203))
+	require.NoError(t, err)
+	assert.Equal(t, byte(ParserAlarmX3), GetPackageType(response))
+	assert.Equal(t, uint16(0x0203), GetPackageSn(response))
+	assert.True(t, ValidatePackage(response))
+
+	response, err = BuildResponse(buildLongPacket(t, ParserInformation, "0004d2", 0x30))
+	require.NoError(t, err)
+	assert.True(t, IsLongPackage(response))
+	assert.Equal(t, []byte{0x00, 0x05}, response[2:4])
+	assert.True(t, ValidatePackage(response))
+
+	response, err = BuildResponse(buildPacket(t, ParserTimeCalibration, "", 0x40))
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().UTC(), decodeDateTime(GetPackageContent(response)), 2*time.Second)
+
+	_, err = BuildResponse(buildPacket(t, ParserLocationGT06, "00", 1))
+	assert.ErrorIs(t, err, ErrNoResponse)
+}
+
+func TestBuildTimeCalibrationResponse(t *testing.T) {
+	tehran := time.FixedZone("IRST", 3*3600+1800)
+	responder := NewResponder(func() time.Time {
+		return time.Date(2023, 3, 1, 2, 4, 5, 0, tehran)
+	})
+
+	response, err := responder.BuildResponse(buildPacket(t, ParserTimeCalibration, "", 0x40))
+	require.NoError(t, err)
+	assert.Equal(t, byte(ParserTimeCalibrationLength), response[2])
+	assert.Equal(t, []byte{0x17, 0x02, 0x1c, 0x16, 0x22, 0x05}, GetPackageContent(response))
+	assert.Equal(t, uint16(0x40), GetPackageSn(response))
+	assert.True(t, ValidatePackage(response))
+}
+
+func TestEncodeOnlineCommand(t *testing.T) {
+	packet := CreatePackageForDevice("RELAY,1#")
+	assert.Equal(t, byte(ParserOnlineCommandProtocol), GetPackageType(packet))
+	assert.Equal(t, byte(0x12), packet[2])
+	assert.Equal(t, "0c00000000", hex.EncodeToString(packet[4:9]))
+	assert.Equal(t, "RELAY,1#", string(packet[9:17]))
+	assert.True(t, CrcChecker(packet))
+
+	packet, err := EncodeOnlineCommand(OnlineCommand{ServerFlag: 0x01020304, Language: LanguageEnglish, Content: "WHERE#"})
+	require.NoError(t, err)
+	assert.True(t, IsNormalPackage(packet))
+	assert.Equal(t, "0a01020304", hex.EncodeToString(packet[4:9]))
+	assert.Equal(t, "WHERE#", string(packet[9:15]))
+	assert.Equal(t, []byte{0x00, LanguageEnglish}, packet[15:17])
+	assert.True(t, CrcChecker(packet))
+
+	long := strings.Repeat("A", 248)
+	packet, err = EncodeOnlineCommand(OnlineCommand{ServerFlag: 7, Language: LanguageChinese, Content: long})
+	require.NoError(t, err)
+	assert.True(t, IsLongPackage(packet))
+	assert.Equal(t, len(packet)-6, int(packet[2])<<8|int(packet[3]))
+	assert.Equal(t, byte(ParserOnlineCommandProtocol), GetPackageType(packet))
+	assert.Equal(t, long, string(packet[10:10+len(long)]))
+	assert.True(t, CrcChecker(packet))
+
+	packet, err = EncodeOnlineCommand(OnlineCommand{ServerFlag: 8, Encoding: EncodingUTF16, Content: "RELAY,1#"})
+	require.NoError(t, err)
+	assert.Equal(t, byte(20), packet[4])
+	assert.Equal(t, "00520045004c00410059002c00310023", hex.EncodeToString(packet[9:25]))
+	assert.True(t, CrcChecker(packet))
+
+	_, err = EncodeOnlineCommand(OnlineCommand{Encoding: EncodingUTF16, Content: strings.Repeat("A", MaxOnlineCommandLength/2+1)})
+	assert.ErrorIs(t, err, ErrCommandTooLong)
+
+	_, err = EncodeOnlineCommand(OnlineCommand{Content: strings.Repeat("A", MaxOnlineCommandLength+1)})
+	assert.ErrorIs(t, err, ErrCommandTooLong)
+	assert.Nil(t, CreatePackageForDevice(strings.Repeat("A", MaxOnlineCommandLength+1)))
+}
@@ -0,0 +1,110 @@
+package concox
+
+import (
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+
+	"github.com/xen0tic/utils/concurrent"
+)
+
+// SerialCounter hands out packet serial numbers from 1 to 0xffff, wrapping back to 1. It is safe for
+// concurrent use and can be held per connection.
+type SerialCounter struct {
+	value atomic.Uint32
+}
+
+func (c *SerialCounter) Next() uint16 {
+	for {
+		current := c.value.Load()
+		next := current + 1
+		if next > 0xffff {
+			next = 1
+		}
+		if c.value.CompareAndSwap(current, next) {
+			return uint16(next)
+		}
+	}
+}
+
+// Current returns the last serial number handed out.
+func (c *SerialCounter) Current() uint16 {
+	return uint16(c.value.Load())
+}
+
+// Set makes the counter continue after value.
+func (c *SerialCounter) Set(value uint16) {
+	c.value.Store(uint32(value))
+}
+
+// SerialGenerator keeps a SerialCounter per device IMEI. Its state can be persisted with Snapshot or
+// json.Marshal and brought back with Restore or json.Unmarshal across gateway restarts. The zero value is
+// ready to use.
+type SerialGenerator struct {
+	once     sync.Once
+	counters concurrent.Map[*SerialCounter]
+}
+
+func NewSerialGenerator() *SerialGenerator {
+	return new(SerialGenerator)
+}
+
+// Next returns the next serial number for imei.
+func (g *SerialGenerator) Next(imei string) uint16 {
+	return g.counter(imei).Next()
+}
+
+// Snapshot returns the last serial number handed out for every IMEI.
+func (g *SerialGenerator) Snapshot() map[string]uint16 {
+	state := make(map[string]uint16)
+	g.init()
+	g.counters.IterCb(func(key string, counter *SerialCounter) {
+		state[key] = counter.Current()
+	})
+	return state
+}
+
+// Restore sets the counters from a snapshot, the next serial number of each IMEI continues after it.
+func (g *SerialGenerator) Restore(state map[string]uint16) {
+	for imei, value := range state {
+		g.counter(imei).Set(value)
+	}
+}
+
+// Remove drops the counter of imei, e.g. when its connection is closed for good.
+func (g *SerialGenerator) Remove(imei string) {
+	g.init()
+	g.counters.Remove(imei)
+}
+
+func (g *SerialGenerator) MarshalJSON() ([]byte, error) {
+	return json.Marshal(g.Snapshot())
+}
+
+func (g *SerialGenerator) UnmarshalJSON(data []byte) error {
+	var state map[string]uint16
+	if err := json.Unmarshal(data, &state); err != nil {
+		return err
+	}
+	g.Restore(state)
+	return nil
+}
+
+func (g *SerialGenerator) init() {
+	g.once.Do(func() {
+		g.counters = concurrent.New[*SerialCounter]()
+	})
+}
+
+func (g *SerialGenerator) counter(imei string) *SerialCounter {
+	g.init()
+	if counter, ok := g.counters.Get(imei); ok {
+		return counter
+	}
+	return g.counters.Upsert(imei, new(SerialCounter), func(exist bool, valueInMap, newValue *SerialCounter) *SerialCounter {
+		if exist {
+			return valueInMap
+		}
+		return newValue
+	})
+}
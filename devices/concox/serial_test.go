@@ -0,0 +1,61 @@
+package concox
+
+import (
+	"encoding/json"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSerialCounterWraps(t *testing.T) {
+	var counter SerialCounter
+	assert.Equal(t, uint16(1), counter.Next())
+
+	counter.Set(0xfffe)
+	assert.Equal(t, uint16(0xffff), counter.Next())
+	assert.Equal(t, uint16(1), counter.Next())
+}
+
+func TestSerialGenerator(t *testing.T) {
+	generator := NewSerialGenerator()
+
+	var wg sync.WaitGroup
+	seen := make([]map[uint16]bool, 4)
+	for i := range seen {
+		seen[i] = make(map[uint16]bool)
+		wg.Add(1)
+		go func(seen map[uint16]bool) {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				seen[generator.Next("868120145233604")] = true
+			}
+		}(seen[i])
+	}
+	wg.Wait()
+
+	all := make(map[uint16]bool)
+	for _, s := range seen {
+		for sn := range s {
+			assert.False(t, all[sn], "duplicate serial %d", sn)
+			all[sn] = true
+		}
+	}
+	assert.Len(t, all, 4000)
+	assert.Equal(t, uint16(1), generator.Next("868120145233605"))
+
+	data, err := json.Marshal(generator)
+	require.NoError(t, err)
+
+	restored := new(SerialGenerator)
+	require.NoError(t, json.Unmarshal(data, restored))
+	assert.Equal(t, map[string]uint16{"868120145233604": 4000, "868120145233605": 1}, restored.Snapshot())
+	assert.Equal(t, uint16(4001), restored.Next("868120145233604"))
+}
+
+func TestSerialGeneratorZeroValue(t *testing.T) {
+	var generator SerialGenerator
+	assert.Equal(t, uint16(1), generator.Next("868120145233604"))
+	assert.Equal(t, map[string]uint16{"868120145233604": 1}, generator.Snapshot())
+}
@@ -0,0 +1,34 @@
+package concox
+
+import (
+	"strings"
+	"unicode/utf16"
+)
+
+// encodeText encodes a command for the device, as UTF-16BE for EncodingUTF16 and as is otherwise.
+func encodeText(text string, encoding byte) []byte {
+	if encoding != EncodingUTF16 {
+		return []byte(text)
+	}
+
+	units := utf16.Encode([]rune(text))
+	result := make([]byte, 0, len(units)*2)
+	for _, unit := range units {
+		result = append(result, byte(unit>>8), byte(unit))
+	}
+	return result
+}
+
+// decodeText decodes device text to UTF-8. UTF-16BE text with an odd length loses its last byte, and
+// bytes that are not valid UTF-8 in ASCII text are replaced with U+FFFD.
+func decodeText(data []byte, unicode bool) string {
+	if !unicode {
+		return strings.ToValidUTF8(string(data), "�")
+	}
+
+	units := make([]uint16, len(data)/2)
+	for i := range units {
+		units[i] = uint16(data[2*i])<<8 | uint16(data[2*i+1])
+	}
+	return string(utf16.Decode(units))
+}
@@ -2,12 +2,12 @@ package concox
 
 import (
 	"bytes"
+	"encoding/binary"
 
-	"github.com/snksoft/crc"
 	"golang.org/x/exp/slices"
 )
 
-var serialNumber uint16 = 0
+var serialNumber SerialCounter
 
 const (
 	ParserLogin                        = 0x01
@@ -35,6 +35,10 @@ const (
 	ParserOnlineCommandProtocol        = 0x80
 )
 
+// MaxOnlineCommandLength is the longest command that fits the one byte command length, which also counts
+// the four byte server flag.
+const MaxOnlineCommandLength = 0xff - 4
+
 const (
 	ParserLoginStr                     = "Login"
 	ParserLocationStr                  = "Location"
@@ -48,6 +52,17 @@ const (
 	ParserOnlineCommandLongResponseStr = "Online Command Response Long"
 )
 
+const (
+	LanguageChinese = 0x01
+	LanguageEnglish = 0x02
+)
+
+// Text encodings of online commands and their responses.
+const (
+	EncodingASCII = 0x01
+	EncodingUTF16 = 0x02
+)
+
 func validPackage() []byte {
 	return []byte{
 		ParserLogin,
@@ -61,34 +76,41 @@ func validPackage() []byte {
 		ParserLocationX3,
 		ParserOnlineCommandLongResponse,
 		ParserAlarmX3,
+		ParserAlarmX3V2,
 		ParserLBSLocationX3,
+		ParserWifiInformation,
 	}
 }
 
 func GeneratePackageSN() (byte, byte) {
-	if serialNumber++; serialNumber > 65535 {
-		serialNumber = 1
-	}
-	return byte(serialNumber >> 8), byte(serialNumber)
+	sn := serialNumber.Next()
+	return byte(sn >> 8), byte(sn)
 }
 
 func GenerateCrc(data []byte) (byte, byte) {
-	ccittCrc := crc.CalculateCRC(crc.X25, data)
-	return byte(ccittCrc >> 8), byte(ccittCrc)
+	sum := Checksum(data)
+	return byte(sum >> 8), byte(sum)
 }
 
 func CrcChecker(data []byte) bool {
-	crack := data[2 : len(data)-4]
-	dataC := data[len(data)-4 : len(data)-2]
-	crc1, crc2 := GenerateCrc(crack)
-	return bytes.Equal(dataC, []byte{crc1, crc2})
+	if len(data) < 6 {
+		return false
+	}
+	sum := Checksum(data[2 : len(data)-4])
+	return binary.BigEndian.Uint16(data[len(data)-4:len(data)-2]) == sum
 }
 
 func GetStartBytes(input []byte) []byte {
+	if len(input) < 2 {
+		return nil
+	}
 	return input[:2]
 }
 
 func GetEndBytes(input []byte) []byte {
+	if len(input) < 2 {
+		return nil
+	}
 	return input[len(input)-2:]
 }
 
@@ -124,37 +146,100 @@ func ValidateEndBytes(input []byte) bool {
 }
 
 func ValidatePackage(input []byte) bool {
-	return ValidateStartBytes(input) && ValidateEndBytes(input) &&
-		slices.Contains(validPackage(), GetPackageType(input)) && CrcChecker(input)
+	return Validate(input) == nil
+}
+
+// Validate checks the framing, length, protocol number and CRC of a device packet and reports the first
+// problem it finds.
+func Validate(input []byte) error {
+	if len(input) < minFrameLength {
+		return ErrShortPacket
+	}
+	if !IsNormalPackage(input) && !IsLongPackage(input) {
+		return ErrBadStart
+	}
+	if !ValidateEndBytes(input) {
+		return ErrBadStop
+	}
+	if size, _ := frameLength(input); size != len(input) {
+		return ErrLengthMismatch
+	}
+	if !slices.Contains(validPackage(), GetPackageType(input)) {
+		return ErrUnknownProtocol
+	}
+	if !CrcChecker(input) {
+		return ErrCRC
+	}
+	return nil
 }
 
 func GetPackageType(input []byte) byte {
-	if IsLongPackage(GetStartBytes(input)) {
-		return input[4]
+	protocol, _ := PackageType(input)
+	return protocol
+}
+
+// PackageType returns the protocol number of a packet, or ErrShortPacket when the packet ends before it.
+func PackageType(input []byte) (byte, error) {
+	offset := 3
+	if IsLongPackage(input) {
+		offset = 4
+	}
+
+	if len(input) <= offset {
+		return 0, ErrShortPacket
 	}
-	
-	return input[3]
+	return input[offset], nil
+}
+
+// OnlineCommand is an online command (0x80) sent to a device. ServerFlag is echoed back by the device in
+// its response so the two can be matched. Language is appended when it is not zero. SerialNumber should
+// come from the device's own sequence, e.g. a SerialGenerator; when it is zero the package-wide counter
+// shared by all devices is used. Content is sent as
+// UTF-16BE when Encoding is EncodingUTF16, for firmware that only accepts Unicode commands, and as is
+// otherwise.
+type OnlineCommand struct {
+	ServerFlag   uint32
+	Language     uint16
+	Encoding     byte
+	SerialNumber uint16
+	Content      string
+}
+
+// EncodeOnlineCommand builds an online command packet, switching to the long (0x79) format when the packet
+// no longer fits a single length byte.
+func EncodeOnlineCommand(command OnlineCommand) ([]byte, error) {
+	text := encodeText(command.Content, command.Encoding)
+	if len(text) > MaxOnlineCommandLength {
+		return nil, ErrCommandTooLong
+	}
+
+	content := make([]byte, 0, len(text)+7)
+	content = append(content, byte(len(text)+4))
+	content = binary.BigEndian.AppendUint32(content, command.ServerFlag)
+	content = append(content, text...)
+	if command.Language != 0 {
+		content = binary.BigEndian.AppendUint16(content, command.Language)
+	}
+
+	sn := command.SerialNumber
+	if sn == 0 {
+		sn = serialNumber.Next()
+	}
+	long := len(content)+5 > 0xff
+
+	return EncodePackage(long, ParserOnlineCommandProtocol, content, sn), nil
 }
 
+// CreatePackageForDevice builds an online command packet for message, numbered from the package-wide
+// counter. It returns nil when message is too long to send.
+//
+// Deprecated: Use EncodeOnlineCommand, which reports ErrCommandTooLong and takes a per-device serial number.
 func CreatePackageForDevice(message string) []byte {
-	result := make([]byte, len(message)+15)
-	msg := []byte(message)
-	
-	result[0] = ParserStartBit
-	result[1] = ParserStartBit
-	result[2] = byte(len(message) + 10)
-	result[3] = ParserOnlineCommandProtocol
-	result[len(result)-6], result[len(result)-5] = GeneratePackageSN()
-	result[4] = byte(len(message) + 4)
-	
-	copy(result[9:9+len(msg)], msg)
-	
-	result[len(result)-4], result[len(result)-3] = GenerateCrc(result[2 : len(result)-4])
-	
-	result[len(result)-2] = ParserEndBitFirst
-	result[len(result)-1] = ParserEndBitEnd
-	
-	return result
+	packet, err := EncodeOnlineCommand(OnlineCommand{Content: message})
+	if err != nil {
+		return nil
+	}
+	return packet
 }
 
 func IsLogin(input []byte) bool {
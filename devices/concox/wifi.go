@@ -0,0 +1,54 @@
+package concox
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/xen0tic/utils/generics"
+)
+
+const wifiAccessPointLength = 7
+
+// DecodeWifiLocation decodes a WiFi information packet (0x2c): the LBS cells followed by the number of
+// access points and a (BSSID, RSSI) pair for each of them.
+func DecodeWifiLocation(input []byte) (generics.WifiLocation, error) {
+	var wifi generics.WifiLocation
+
+	_, content, err := packetContent(input, dateTimeLength, ParserWifiInformation)
+	if err != nil {
+		return wifi, err
+	}
+
+	offset, err := decodeLbsCells(content[dateTimeLength:], &wifi.Lbs)
+	if err != nil {
+		return wifi, err
+	}
+	offset += dateTimeLength
+
+	if len(content) < offset+1 {
+		return wifi, ErrShortPacket
+	}
+	count := int(content[offset])
+	offset++
+
+	if len(content) < offset+count*wifiAccessPointLength {
+		return wifi, ErrShortPacket
+	}
+
+	wifi.AccessPoints = make([]generics.WifiAccessPoint, 0, count)
+	for i := 0; i < count; i++ {
+		wifi.AccessPoints = append(wifi.AccessPoints, generics.WifiAccessPoint{
+			BSSID: net.HardwareAddr(content[offset : offset+6]).String(),
+			RSSI:  int(content[offset+6]),
+		})
+		offset += wifiAccessPointLength
+	}
+
+	wifi.Timestamp = decodeDateTime(content)
+	wifi.Date = wifi.Timestamp.Format(generics.DateFormat)
+	wifi.SerialNumber = fmt.Sprintf("%04x", GetPackageSn(input))
+	wifi.Lbs.Date = wifi.Date
+	wifi.Lbs.SerialNumber = wifi.SerialNumber
+
+	return wifi, nil
+}
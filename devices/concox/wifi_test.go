@@ -0,0 +1,28 @@
+package concox
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeWifiLocation(t *testing.T) {
+	neighbours := "287e001fb93c" + "287f001fba3b" + "2880001fbb3a" + "2881001fbc39" + "2882001fbd38" + "2883001fbe37"
+	packet := buildPacket(t, ParserWifiInformation,
+		"0b081d112e1001cc00287d001fb83d"+neighbours+"ff"+"02"+"a0b1c2d3e4f545"+"0011223344555a", 0x21)
+	require.True(t, ValidatePackage(packet))
+
+	wifi, err := DecodeWifiLocation(packet)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2011, 8, 29, 17, 46, 16, 0, time.UTC), wifi.Timestamp)
+	assert.Equal(t, uint16(460), wifi.Lbs.MCC)
+	assert.Equal(t, uint16(0x2883), wifi.Lbs.NLAC6)
+	assert.Equal(t, 0xff, wifi.Lbs.TimeAdvance)
+	require.Len(t, wifi.AccessPoints, 2)
+	assert.Equal(t, "a0:b1:c2:d3:e4:f5", wifi.AccessPoints[0].BSSID)
+	assert.Equal(t, 0x45, wifi.AccessPoints[0].RSSI)
+	assert.Equal(t, "00:11:22:33:44:55", wifi.AccessPoints[1].BSSID)
+	assert.Equal(t, "0021", wifi.SerialNumber)
+}
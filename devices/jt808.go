@@ -0,0 +1,80 @@
+package devices
+
+import (
+	"errors"
+
+	"github.com/xen0tic/utils/devices/concox"
+	"github.com/xen0tic/utils/devices/jt808"
+	"github.com/xen0tic/utils/generics"
+)
+
+var (
+	// ErrUnsupportedCommand occurs when a protocol cannot send text commands through EncodeCommand.
+	ErrUnsupportedCommand = errors.New("devices: protocol does not support text commands")
+	// ErrAuthentication occurs when BuildAck is given a JT/T 808 registration or authentication. The
+	// gateway issues and verifies authentication codes itself and answers with
+	// jt808.EncodeRegistrationResponse or jt808.EncodeGeneralResponse.
+	ErrAuthentication = errors.New("devices: registration and authentication are answered by the gateway")
+)
+
+// jt808Protocol serves JT/T 808 terminals, 2013 and 2019 headers alike. The platform numbers its
+// responses per terminal phone.
+type jt808Protocol struct {
+	serials *concox.SerialGenerator
+}
+
+func init() {
+	Register(generics.DEVICE_TYPE_JT808, jt808Protocol{serials: concox.NewSerialGenerator()})
+}
+
+func (jt808Protocol) Detect(data []byte) bool {
+	return len(data) >= 2 && data[0] == jt808.FlagByte && data[1] != jt808.FlagByte
+}
+
+func (jt808Protocol) Frame(data []byte) ([][]byte, []byte) {
+	return jt808.Frame(data)
+}
+
+// Decode returns a jt808.Registration, a jt808.LocationReport or the authentication code for the messages
+// it knows, and the jt808.Message itself for the others.
+func (jt808Protocol) Decode(packet []byte) (interface{}, error) {
+	message, err := jt808.Decode(packet)
+	if err != nil {
+		return nil, err
+	}
+
+	switch message.Header.MessageID {
+	case jt808.MessageRegistration:
+		return jt808.DecodeRegistration(message)
+	case jt808.MessageAuthentication:
+		return jt808.DecodeAuthentication(message)
+	case jt808.MessageLocationReport:
+		return jt808.DecodeLocationReport(message)
+	}
+	return message, nil
+}
+
+// BuildAck answers every terminal message with a successful platform general response, except terminal
+// general responses, which are not answered, and registrations and authentications, for which it returns
+// ErrAuthentication.
+func (p jt808Protocol) BuildAck(packet []byte) ([]byte, error) {
+	message, err := jt808.Decode(packet)
+	if err != nil {
+		return nil, err
+	}
+
+	header := message.Header
+	switch header.MessageID {
+	case jt808.MessageTerminalGeneralResponse:
+		return nil, jt808.ErrNoResponse
+	case jt808.MessageRegistration, jt808.MessageAuthentication:
+		return nil, ErrAuthentication
+	}
+	return jt808.EncodeGeneralResponse(header.Phone, p.serials.Next(header.Phone), header, jt808.ResultSuccess)
+}
+
+// EncodeCommand is not supported: JT/T 808 messages are addressed by the terminal phone, which a text
+// command does not carry.
+func (jt808Protocol) EncodeCommand(string, uint16) ([]byte, error) {
+	return nil, ErrUnsupportedCommand
+}
@@ -0,0 +1,273 @@
+package jt808
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+const (
+	MessageTerminalGeneralResponse = 0x0001
+	MessageHeartBeat               = 0x0002
+	MessageRegistration            = 0x0100
+	MessageLogout                  = 0x0003
+	MessageAuthentication          = 0x0102
+	MessageLocationReport          = 0x0200
+	MessagePlatformGeneralResponse = 0x8001
+	MessageRegistrationResponse    = 0x8100
+)
+
+const (
+	FlagByte   = 0x7e
+	EscapeByte = 0x7d
+
+	headerLength        = 12
+	headerLength2019    = 17
+	subpackageLength    = 4
+	phoneLength         = 6
+	phoneLength2019     = 10
+	bodyLengthMask      = 0x03ff
+	versionFlag         = 0x4000
+	subpackageFlag      = 0x2000
+	encryptionMask      = 0x1c00
+	encryptionShift     = 10
+	maxBodyLength       = bodyLengthMask
+	escapedFlagByte     = 0x02
+	escapedEscapeByte   = 0x01
+	minUnescapedMessage = headerLength + 1
+)
+
+var (
+	// ErrShortMessage occurs when a message is too short for its header or its body.
+	ErrShortMessage = errors.New("jt808: message is too short")
+	// ErrBadFlag occurs when a frame does not start and end with 0x7e.
+	ErrBadFlag = errors.New("jt808: frame is not delimited by 0x7e")
+	// ErrBadEscape occurs when 0x7d is followed by anything but 0x01 or 0x02.
+	ErrBadEscape = errors.New("jt808: invalid escape sequence")
+	// ErrChecksum occurs when the XOR checksum does not match the message.
+	ErrChecksum = errors.New("jt808: checksum mismatch")
+	// ErrLengthMismatch occurs when the body length in the header does not match the body.
+	ErrLengthMismatch = errors.New("jt808: body length does not match the header")
+	// ErrUnexpectedMessage occurs when a decoder is given a message of another message ID.
+	ErrUnexpectedMessage = errors.New("jt808: unexpected message id")
+	// ErrBodyTooLong occurs when a body does not fit the ten bit body length.
+	ErrBodyTooLong = errors.New("jt808: body is too long")
+	// ErrBadPhone occurs when a phone number has more than 12 digits, 20 in the 2019 header, or anything
+	// but digits.
+	ErrBadPhone = errors.New("jt808: invalid terminal phone number")
+	// ErrNoResponse occurs when a message is not answered by the platform.
+	ErrNoResponse = errors.New("jt808: message has no platform response")
+)
+
+// Header is the message header. PackageCount and PackageIndex are only set for subpackaged messages.
+//
+// Versioned is set by bit 14 of the message properties: the 2019 header, which adds a protocol Version
+// byte and widens the phone number to 10 BCD bytes.
+type Header struct {
+	MessageID    uint16 `json:"messageId"`
+	Versioned    bool   `json:"versioned,omitempty"`
+	Version      byte   `json:"version,omitempty"`
+	Encryption   byte   `json:"encryption"`
+	Subpackage   bool   `json:"subpackage"`
+	Phone        string `json:"phone"`
+	SerialNumber uint16 `json:"serialNumber"`
+	PackageCount uint16 `json:"packageCount,omitempty"`
+	PackageIndex uint16 `json:"packageIndex,omitempty"`
+}
+
+type Message struct {
+	Header Header
+	Body   []byte
+}
+
+// Escape replaces 0x7e with 0x7d 0x02 and 0x7d with 0x7d 0x01.
+func Escape(input []byte) []byte {
+	result := make([]byte, 0, len(input)+len(input)/8)
+	for _, item := range input {
+		switch item {
+		case FlagByte:
+			result = append(result, EscapeByte, escapedFlagByte)
+		case EscapeByte:
+			result = append(result, EscapeByte, escapedEscapeByte)
+		default:
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// Unescape reverses Escape.
+func Unescape(input []byte) ([]byte, error) {
+	result := make([]byte, 0, len(input))
+	for i := 0; i < len(input); i++ {
+		if input[i] != EscapeByte {
+			result = append(result, input[i])
+			continue
+		}
+
+		if i+1 >= len(input) {
+			return nil, ErrBadEscape
+		}
+		switch input[i+1] {
+		case escapedFlagByte:
+			result = append(result, FlagByte)
+		case escapedEscapeByte:
+			result = append(result, EscapeByte)
+		default:
+			return nil, ErrBadEscape
+		}
+		i++
+	}
+	return result, nil
+}
+
+// Checksum is the XOR of every byte of the header and body.
+func Checksum(input []byte) byte {
+	var sum byte
+	for _, item := range input {
+		sum ^= item
+	}
+	return sum
+}
+
+// Frame splits data into frames delimited by 0x7e and returns the bytes left for the next read. Bytes
+// before the first flag are dropped.
+func Frame(data []byte) ([][]byte, []byte) {
+	var frames [][]byte
+
+	for {
+		start := bytes.IndexByte(data, FlagByte)
+		if start < 0 {
+			return frames, nil
+		}
+		data = data[start:]
+
+		end := bytes.IndexByte(data[1:], FlagByte)
+		if end < 0 {
+			return frames, data
+		}
+		end++
+
+		// Two flags in a row are the end of one frame and the start of the next.
+		if end == 1 {
+			data = data[1:]
+			continue
+		}
+
+		frames = append(frames, append([]byte(nil), data[:end+1]...))
+		data = data[end+1:]
+	}
+}
+
+// Decode unescapes a frame, checks its checksum and body length and splits it into header and body.
+func Decode(frame []byte) (Message, error) {
+	var message Message
+
+	if len(frame) < 2 || frame[0] != FlagByte || frame[len(frame)-1] != FlagByte {
+		return message, ErrBadFlag
+	}
+
+	data, err := Unescape(frame[1 : len(frame)-1])
+	if err != nil {
+		return message, err
+	}
+	if len(data) < minUnescapedMessage {
+		return message, ErrShortMessage
+	}
+	if Checksum(data[:len(data)-1]) != data[len(data)-1] {
+		return message, ErrChecksum
+	}
+	data = data[:len(data)-1]
+
+	properties := binary.BigEndian.Uint16(data[2:4])
+	message.Header = Header{
+		MessageID:  binary.BigEndian.Uint16(data[0:2]),
+		Versioned:  properties&versionFlag != 0,
+		Encryption: byte((properties & encryptionMask) >> encryptionShift),
+		Subpackage: properties&subpackageFlag != 0,
+	}
+
+	offset := headerLength
+	if message.Header.Versioned {
+		if len(data) < headerLength2019 {
+			return message, ErrShortMessage
+		}
+		message.Header.Version = data[4]
+		message.Header.Phone = hex.EncodeToString(data[5 : 5+phoneLength2019])
+		offset = headerLength2019
+	} else {
+		message.Header.Phone = hex.EncodeToString(data[4 : 4+phoneLength])
+	}
+	message.Header.SerialNumber = binary.BigEndian.Uint16(data[offset-2 : offset])
+
+	if message.Header.Subpackage {
+		if len(data) < offset+subpackageLength {
+			return message, ErrShortMessage
+		}
+		message.Header.PackageCount = binary.BigEndian.Uint16(data[offset : offset+2])
+		message.Header.PackageIndex = binary.BigEndian.Uint16(data[offset+2 : offset+4])
+		offset += subpackageLength
+	}
+
+	if int(properties&bodyLengthMask) != len(data)-offset {
+		return message, ErrLengthMismatch
+	}
+	message.Body = data[offset:]
+
+	return message, nil
+}
+
+// Encode builds a single package frame with the 2013 header: header, body, checksum, escaping and flags.
+func Encode(messageID uint16, phone string, serialNumber uint16, body []byte) ([]byte, error) {
+	return EncodeMessage(Header{MessageID: messageID, Phone: phone, SerialNumber: serialNumber}, body)
+}
+
+// EncodeMessage builds a single package frame, with the 2019 header when header.Versioned is set.
+// Encryption and subpackaging are not supported.
+func EncodeMessage(header Header, body []byte) ([]byte, error) {
+	if len(body) > maxBodyLength {
+		return nil, ErrBodyTooLong
+	}
+
+	properties := uint16(len(body))
+	size := phoneLength
+	if header.Versioned {
+		properties |= versionFlag
+		size = phoneLength2019
+	}
+
+	phoneBCD, err := encodePhone(header.Phone, size)
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]byte, 0, headerLength2019+len(body)+1)
+	data = binary.BigEndian.AppendUint16(data, header.MessageID)
+	data = binary.BigEndian.AppendUint16(data, properties)
+	if header.Versioned {
+		data = append(data, header.Version)
+	}
+	data = append(data, phoneBCD...)
+	data = binary.BigEndian.AppendUint16(data, header.SerialNumber)
+	data = append(data, body...)
+	data = append(data, Checksum(data))
+
+	result := make([]byte, 0, len(data)+8)
+	result = append(result, FlagByte)
+	result = append(result, Escape(data)...)
+	return append(result, FlagByte), nil
+}
+
+// EncodePhone encodes a terminal phone number as 6 BCD bytes, left padded with zeros.
+func EncodePhone(phone string) ([]byte, error) {
+	return encodePhone(phone, phoneLength)
+}
+
+func encodePhone(phone string, size int) ([]byte, error) {
+	if len(phone) > size*2 || strings.Trim(phone, "0123456789") != "" {
+		return nil, ErrBadPhone
+	}
+	return hex.DecodeString(strings.Repeat("0", size*2-len(phone)) + phone)
+}
@@ -0,0 +1,145 @@
+package jt808
+
+import (
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEscape(t *testing.T) {
+	input := []byte{0x30, 0x7e, 0x08, 0x7d, 0x55}
+	escaped := Escape(input)
+	assert.Equal(t, []byte{0x30, 0x7d, 0x02, 0x08, 0x7d, 0x01, 0x55}, escaped)
+
+	unescaped, err := Unescape(escaped)
+	require.NoError(t, err)
+	assert.Equal(t, input, unescaped)
+
+	_, err = Unescape([]byte{0x7d, 0x03})
+	assert.ErrorIs(t, err, ErrBadEscape)
+	_, err = Unescape([]byte{0x7d})
+	assert.ErrorIs(t, err, ErrBadEscape)
+}
+
+func TestLocationReport(t *testing.T) {
+	body, _ := hex.DecodeString("00000001" + "0000000f" + "016019f0" + "06cc0d90" + "0032" + "0258" + "00b4" + "230301103045" + "01040000007e")
+	frame, err := Encode(MessageLocationReport, "13912345678", 0x7e7d, body)
+	require.NoError(t, err)
+	assert.Equal(t, byte(FlagByte), frame[0])
+	assert.Equal(t, byte(FlagByte), frame[len(frame)-1])
+
+	stream := append(append([]byte{0x00, 0x01}, frame...), frame[:5]...)
+	frames, rest := Frame(stream)
+	require.Len(t, frames, 1)
+	assert.Equal(t, frame, frames[0])
+	assert.Equal(t, frame[:5], rest)
+
+	message, err := Decode(frames[0])
+	require.NoError(t, err)
+	assert.Equal(t, uint16(MessageLocationReport), message.Header.MessageID)
+	assert.Equal(t, "013912345678", message.Header.Phone)
+	assert.Equal(t, uint16(0x7e7d), message.Header.SerialNumber)
+
+	report, err := DecodeLocationReport(message)
+	require.NoError(t, err)
+	assert.Equal(t, -23.075312, report.Latitude)
+	assert.Equal(t, -114.036112, report.Longitude)
+	assert.Equal(t, uint16(50), report.Altitude)
+	assert.Equal(t, 60.0, report.Speed)
+	assert.Equal(t, uint16(180), report.Direction)
+	assert.Equal(t, time.Date(2023, 3, 1, 2, 30, 45, 0, time.UTC), report.Timestamp)
+	assert.Equal(t, []byte{0x01, 0x04, 0x00, 0x00, 0x00, 0x7e}, report.Extra)
+
+	location := report.ToLocation(message.Header.SerialNumber)
+	assert.Equal(t, "-23.075312", location.Lat)
+	assert.Equal(t, "60", location.Speed)
+	assert.False(t, location.AccOff)
+
+	alarm, ok := report.ToAlarm(message.Header.SerialNumber)
+	require.True(t, ok)
+	assert.Equal(t, "SOS", alarm.AlarmMode)
+
+	corrupt := append([]byte(nil), frame...)
+	corrupt[len(corrupt)-2] ^= 0x01
+	_, err = Decode(corrupt)
+	assert.Error(t, err)
+}
+
+func TestRegistration(t *testing.T) {
+	body := []byte{0x00, 0x1f, 0x00, 0x73}
+	body = append(body, []byte("ABCDE")...)
+	body = append(body, []byte("MODEL-1\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00")...)
+	body = append(body, []byte("T000001")...)
+	body = append(body, 0x01, 0xbe, 0xa9)
+
+	frame, err := Encode(MessageRegistration, "13912345678", 1, body)
+	require.NoError(t, err)
+	message, err := Decode(frame)
+	require.NoError(t, err)
+
+	registration, err := DecodeRegistration(message)
+	require.NoError(t, err)
+	assert.Equal(t, Registration{
+		Province:     0x1f,
+		City:         0x73,
+		Manufacturer: "ABCDE",
+		Model:        "MODEL-1",
+		TerminalID:   "T000001",
+		PlateColor:   1,
+		Plate:        []byte{0xbe, 0xa9},
+	}, registration)
+
+	response, err := EncodeRegistrationResponse("13912345678", 9, message.Header, ResultSuccess, "AUTH")
+	require.NoError(t, err)
+	decoded, err := Decode(response)
+	require.NoError(t, err)
+	assert.Equal(t, uint16(MessageRegistrationResponse), decoded.Header.MessageID)
+	assert.Equal(t, append([]byte{0x00, 0x01, ResultSuccess}, "AUTH"...), decoded.Body)
+
+	response, err = EncodeGeneralResponse("13912345678", 10, message.Header, ResultSuccess)
+	require.NoError(t, err)
+	decoded, err = Decode(response)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0x00, 0x01, 0x01, 0x00, ResultSuccess}, decoded.Body)
+
+	_, err = Encode(MessageHeartBeat, "1391234567x", 1, nil)
+	assert.ErrorIs(t, err, ErrBadPhone)
+}
+
+func TestVersion2019(t *testing.T) {
+	header := Header{MessageID: MessageHeartBeat, Versioned: true, Version: 1, Phone: "12345678901234567890", SerialNumber: 3}
+	frame, err := EncodeMessage(header, nil)
+	require.NoError(t, err)
+
+	message, err := Decode(frame)
+	require.NoError(t, err)
+	assert.Equal(t, header, message.Header)
+	assert.Empty(t, message.Body)
+
+	response, err := EncodeGeneralResponse(message.Header.Phone, 4, message.Header, ResultSuccess)
+	require.NoError(t, err)
+	decoded, err := Decode(response)
+	require.NoError(t, err)
+	assert.True(t, decoded.Header.Versioned)
+	assert.Equal(t, "12345678901234567890", decoded.Header.Phone)
+	assert.Equal(t, []byte{0x00, 0x03, 0x00, 0x02, ResultSuccess}, decoded.Body)
+
+	body := []byte{0x00, 0x1f, 0x00, 0x73}
+	body = append(body, []byte("MANUFACTURE")...)
+	body = append(body, make([]byte, 30)...)
+	body = append(body, []byte("TERMINAL-2019")...)
+	body = append(body, make([]byte, 17)...)
+	body = append(body, 0x01, 'A')
+	frame, err = EncodeMessage(Header{MessageID: MessageRegistration, Versioned: true, Version: 1, Phone: "13912345678"}, body)
+	require.NoError(t, err)
+	message, err = Decode(frame)
+	require.NoError(t, err)
+	registration, err := DecodeRegistration(message)
+	require.NoError(t, err)
+	assert.Equal(t, "MANUFACTURE", registration.Manufacturer)
+	assert.Equal(t, "TERMINAL-2019", registration.TerminalID)
+	assert.Equal(t, []byte("A"), registration.Plate)
+}
@@ -0,0 +1,259 @@
+package jt808
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xen0tic/utils/generics"
+)
+
+const (
+	ResultSuccess     = 0x00
+	ResultFailure     = 0x01
+	ResultBadMessage  = 0x02
+	ResultUnsupported = 0x03
+	ResultAlarmAck    = 0x04
+)
+
+const (
+	StatusAcc        = 1 << 0
+	StatusPositioned = 1 << 1
+	StatusSouth      = 1 << 2
+	StatusWest       = 1 << 3
+)
+
+const (
+	AlarmEmergency           = 1 << 0
+	AlarmOverSpeed           = 1 << 1
+	AlarmFatigueDriving      = 1 << 2
+	AlarmEarlyWarning        = 1 << 3
+	AlarmGnssFault           = 1 << 4
+	AlarmGnssAntennaCut      = 1 << 5
+	AlarmGnssAntennaShort    = 1 << 6
+	AlarmUnderVoltage        = 1 << 7
+	AlarmPowerCut            = 1 << 8
+	AlarmTimeout             = 1 << 19
+	AlarmArea                = 1 << 20
+	AlarmRoute               = 1 << 21
+	AlarmIllegalIgnition     = 1 << 27
+	AlarmIllegalDisplacement = 1 << 28
+	AlarmCollision           = 1 << 29
+	AlarmRollover            = 1 << 30
+)
+
+const locationReportLength = 28
+
+// alarmModes follows the bit order of the alarm flags, so the first set bit names the alarm mode.
+var alarmModes = []struct {
+	flag uint32
+	name string
+}{
+	{AlarmEmergency, "SOS"},
+	{AlarmOverSpeed, "Over Speed"},
+	{AlarmFatigueDriving, "Fatigue Driving"},
+	{AlarmEarlyWarning, "Early Warning"},
+	{AlarmGnssFault, "GNSS Fault"},
+	{AlarmGnssAntennaCut, "GNSS Antenna Cut"},
+	{AlarmGnssAntennaShort, "GNSS Antenna Short"},
+	{AlarmUnderVoltage, "External Low Battery"},
+	{AlarmPowerCut, "Power Cut"},
+	{AlarmTimeout, "Parking Timeout"},
+	{AlarmArea, "Area"},
+	{AlarmRoute, "Route"},
+	{AlarmIllegalIgnition, "Illegal Ignition"},
+	{AlarmIllegalDisplacement, "Moving"},
+	{AlarmCollision, "Collision"},
+	{AlarmRollover, "Rollover"},
+}
+
+// chinaStandardTime is the zone of the BCD time in location reports.
+var chinaStandardTime = time.FixedZone("CST", 8*3600)
+
+// Registration is a terminal registration (0x0100) body. Plate is left as sent, most terminals use GBK.
+type Registration struct {
+	Province     uint16 `json:"province"`
+	City         uint16 `json:"city"`
+	Manufacturer string `json:"manufacturer"`
+	Model        string `json:"model"`
+	TerminalID   string `json:"terminalId"`
+	PlateColor   byte   `json:"plateColor"`
+	Plate        []byte `json:"plate"`
+}
+
+// LocationReport is a location report (0x0200) body without its additional information items.
+type LocationReport struct {
+	Alarm     uint32    `json:"alarm"`
+	Status    uint32    `json:"status"`
+	Latitude  float64   `json:"latitude"`
+	Longitude float64   `json:"longitude"`
+	Altitude  uint16    `json:"altitude"`
+	Speed     float64   `json:"speed"`
+	Direction uint16    `json:"direction"`
+	Timestamp time.Time `json:"timestamp"`
+	Extra     []byte    `json:"extra,omitempty"`
+}
+
+func DecodeRegistration(message Message) (Registration, error) {
+	var registration Registration
+
+	if message.Header.MessageID != MessageRegistration {
+		return registration, ErrUnexpectedMessage
+	}
+	body := message.Body
+	// The 2019 registration widens the manufacturer to 11 bytes and the model and terminal ID to 30.
+	manufacturer, model, terminalID := 5, 20, 7
+	if message.Header.Versioned {
+		manufacturer, model, terminalID = 11, 30, 30
+	}
+	if len(body) < 4+manufacturer+model+terminalID+1 {
+		return registration, ErrShortMessage
+	}
+
+	registration.Province = binary.BigEndian.Uint16(body[0:2])
+	registration.City = binary.BigEndian.Uint16(body[2:4])
+	offset := 4
+	registration.Manufacturer = trimString(body[offset : offset+manufacturer])
+	offset += manufacturer
+	registration.Model = trimString(body[offset : offset+model])
+	offset += model
+	registration.TerminalID = trimString(body[offset : offset+terminalID])
+	offset += terminalID
+	registration.PlateColor = body[offset]
+	registration.Plate = append([]byte(nil), body[offset+1:]...)
+
+	return registration, nil
+}
+
+// DecodeAuthentication returns the authentication code of a terminal authentication (0x0102) body.
+func DecodeAuthentication(message Message) (string, error) {
+	if message.Header.MessageID != MessageAuthentication {
+		return "", ErrUnexpectedMessage
+	}
+	return trimString(message.Body), nil
+}
+
+func DecodeLocationReport(message Message) (LocationReport, error) {
+	var report LocationReport
+
+	if message.Header.MessageID != MessageLocationReport {
+		return report, ErrUnexpectedMessage
+	}
+	body := message.Body
+	if len(body) < locationReportLength {
+		return report, ErrShortMessage
+	}
+
+	timestamp, err := time.ParseInLocation("060102150405", hex.EncodeToString(body[22:28]), chinaStandardTime)
+	if err != nil {
+		return report, err
+	}
+
+	report.Alarm = binary.BigEndian.Uint32(body[0:4])
+	report.Status = binary.BigEndian.Uint32(body[4:8])
+	report.Latitude = float64(binary.BigEndian.Uint32(body[8:12])) / 1e6
+	report.Longitude = float64(binary.BigEndian.Uint32(body[12:16])) / 1e6
+	report.Altitude = binary.BigEndian.Uint16(body[16:18])
+	report.Speed = float64(binary.BigEndian.Uint16(body[18:20])) / 10
+	report.Direction = binary.BigEndian.Uint16(body[20:22])
+	report.Timestamp = timestamp.UTC()
+	report.Extra = append([]byte(nil), body[28:]...)
+
+	if report.Status&StatusSouth != 0 {
+		report.Latitude = -report.Latitude
+	}
+	if report.Status&StatusWest != 0 {
+		report.Longitude = -report.Longitude
+	}
+
+	return report, nil
+}
+
+// ToLocation converts the report into a generics.Location. JT/T 808 carries no cell information, so the
+// LBS fields stay empty.
+func (r LocationReport) ToLocation(serialNumber uint16) generics.Location {
+	return generics.Location{
+		Lat:          strconv.FormatFloat(r.Latitude, 'f', 6, 64),
+		Lng:          strconv.FormatFloat(r.Longitude, 'f', 6, 64),
+		Speed:        strconv.Itoa(int(r.Speed)),
+		Course:       strconv.Itoa(int(r.Direction)),
+		AccOff:       r.Status&StatusAcc == 0,
+		SerialNumber: serialNumber,
+		Date:         r.Timestamp.Format(generics.DateFormat),
+		Timestamp:    r.Timestamp,
+		Nanoseconds:  r.Timestamp.UnixNano(),
+	}
+}
+
+// ToAlarm converts the report into a generics.Alarm, or returns false when no alarm flag is set.
+func (r LocationReport) ToAlarm(serialNumber uint16) (generics.Alarm, bool) {
+	mode := AlarmModeString(r.Alarm)
+	if mode == "" {
+		return generics.Alarm{}, false
+	}
+
+	location := r.ToLocation(serialNumber)
+	return generics.Alarm{
+		Latitude:            location.Lat,
+		Longitude:           location.Lng,
+		Speed:               location.Speed,
+		Course:              location.Course,
+		TerminalInformation: fmt.Sprintf("%08x", r.Status),
+		AlarmMode:           mode,
+		Date:                location.Date,
+		SerialNumber:        fmt.Sprintf("%04x", serialNumber),
+		Timestamp:           r.Timestamp,
+	}, true
+}
+
+// AlarmModeString names the lowest alarm flag set, or returns an empty string when there is none.
+func AlarmModeString(alarm uint32) string {
+	for _, mode := range alarmModes {
+		if alarm&mode.flag != 0 {
+			return mode.name
+		}
+	}
+	if alarm != 0 {
+		return "Unknown"
+	}
+	return ""
+}
+
+// EncodeGeneralResponse builds a platform general response (0x8001) to the message with the given header,
+// in the header layout of that message.
+func EncodeGeneralResponse(phone string, serialNumber uint16, header Header, result byte) ([]byte, error) {
+	body := make([]byte, 0, 5)
+	body = binary.BigEndian.AppendUint16(body, header.SerialNumber)
+	body = binary.BigEndian.AppendUint16(body, header.MessageID)
+	body = append(body, result)
+	return EncodeMessage(responseHeader(MessagePlatformGeneralResponse, phone, serialNumber, header), body)
+}
+
+// EncodeRegistrationResponse builds a registration response (0x8100). The authentication code is only
+// sent when result is ResultSuccess.
+func EncodeRegistrationResponse(phone string, serialNumber uint16, header Header, result byte, authCode string) ([]byte, error) {
+	body := make([]byte, 0, 3+len(authCode))
+	body = binary.BigEndian.AppendUint16(body, header.SerialNumber)
+	body = append(body, result)
+	if result == ResultSuccess {
+		body = append(body, authCode...)
+	}
+	return EncodeMessage(responseHeader(MessageRegistrationResponse, phone, serialNumber, header), body)
+}
+
+func responseHeader(messageID uint16, phone string, serialNumber uint16, request Header) Header {
+	return Header{
+		MessageID:    messageID,
+		Versioned:    request.Versioned,
+		Version:      request.Version,
+		Phone:        phone,
+		SerialNumber: serialNumber,
+	}
+}
+
+func trimString(input []byte) string {
+	return strings.TrimRight(string(input), "\x00 ")
+}
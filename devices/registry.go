@@ -0,0 +1,100 @@
+package devices
+
+import (
+	"errors"
+	"sort"
+	"sync"
+
+	"github.com/xen0tic/utils/generics"
+)
+
+// ErrUnsupportedDevice occurs when no registered protocol handles a device type or a packet.
+var ErrUnsupportedDevice = errors.New("devices: unsupported device type")
+
+// Protocol is the wire protocol of a device family.
+type Protocol interface {
+	// Detect reports whether data starts like a packet of this protocol.
+	Detect(data []byte) bool
+	// Frame splits data into whole packets and returns the bytes left for the next read.
+	Frame(data []byte) ([][]byte, []byte)
+	// Decode validates a single packet and returns its typed content.
+	Decode(packet []byte) (interface{}, error)
+	// BuildAck returns the acknowledgement the server must send for packet. It fails for packets the
+	// server must not answer, and for those the caller must answer itself, such as the JT/T 808
+	// registrations and authentications that carry an authentication code (ErrAuthentication).
+	BuildAck(packet []byte) ([]byte, error)
+	// EncodeCommand builds the packet that sends a text command to the device, numbered serial. The
+	// serial should come from the device's own sequence, e.g. a concox.SerialGenerator.
+	EncodeCommand(command string, serial uint16) ([]byte, error)
+}
+
+var (
+	mu        sync.RWMutex
+	protocols = make(map[generics.DeviceType]Protocol)
+)
+
+// Register makes a protocol available for a device type, replacing any protocol registered before.
+func Register(deviceType generics.DeviceType, protocol Protocol) {
+	mu.Lock()
+	defer mu.Unlock()
+	protocols[deviceType] = protocol
+}
+
+func Lookup(deviceType generics.DeviceType) (Protocol, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	protocol, ok := protocols[deviceType]
+	return protocol, ok
+}
+
+// Detect returns the first registered device type, in DeviceType order, whose protocol recognises data.
+func Detect(data []byte) (generics.DeviceType, Protocol, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	types := make([]generics.DeviceType, 0, len(protocols))
+	for deviceType := range protocols {
+		types = append(types, deviceType)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i] < types[j] })
+
+	for _, deviceType := range types {
+		if protocols[deviceType].Detect(data) {
+			return deviceType, protocols[deviceType], true
+		}
+	}
+	return generics.DEVICE_TYPE_UNSPECIFIED, nil, false
+}
+
+// Parse decodes request.Data with the protocol of request.DeviceType, or with the detected protocol when
+// the device type is not specified.
+func Parse(request generics.ParseRequest) (interface{}, error) {
+	protocol, err := resolve(request)
+	if err != nil {
+		return nil, err
+	}
+	return protocol.Decode(request.Data)
+}
+
+// BuildAck builds the acknowledgement for request.Data, resolving the protocol like Parse.
+func BuildAck(request generics.ParseRequest) ([]byte, error) {
+	protocol, err := resolve(request)
+	if err != nil {
+		return nil, err
+	}
+	return protocol.BuildAck(request.Data)
+}
+
+func resolve(request generics.ParseRequest) (Protocol, error) {
+	if request.DeviceType != generics.DEVICE_TYPE_UNSPECIFIED {
+		if protocol, ok := Lookup(request.DeviceType); ok {
+			return protocol, nil
+		}
+		return nil, ErrUnsupportedDevice
+	}
+
+	if _, protocol, ok := Detect(request.Data); ok {
+		return protocol, nil
+	}
+	return nil, ErrUnsupportedDevice
+}
@@ -0,0 +1,79 @@
+package devices
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xen0tic/utils/devices/concox"
+	"github.com/xen0tic/utils/devices/jt808"
+	"github.com/xen0tic/utils/generics"
+)
+
+func TestParse(t *testing.T) {
+	login := concox.EncodePackage(false, concox.ParserLogin, []byte{0x08, 0x68, 0x12, 0x01, 0x45, 0x23, 0x36, 0x04}, 1)
+
+	deviceType, _, ok := Detect(login)
+	require.True(t, ok)
+	assert.Equal(t, generics.DEVICE_TYPE_GT06, deviceType)
+
+	result, err := Parse(generics.ParseRequest{Data: login})
+	require.NoError(t, err)
+	assert.Equal(t, generics.Imei("868120145233604"), result.(concox.Login).Imei)
+
+	ack, err := BuildAck(generics.ParseRequest{Data: login, DeviceType: generics.DEVICE_TYPE_X3})
+	require.NoError(t, err)
+	assert.Equal(t, uint16(1), concox.GetPackageSn(ack))
+
+	wifi := concox.EncodePackage(false, concox.ParserWifiInformation, nil, 2)
+	deviceType, _, ok = Detect(wifi)
+	require.True(t, ok)
+	assert.Equal(t, generics.DEVICE_TYPE_X3, deviceType)
+
+	protocol, ok := Lookup(generics.DEVICE_TYPE_GT06)
+	require.True(t, ok)
+	frames, rest := protocol.Frame(append(append([]byte{}, login...), login[:4]...))
+	assert.Equal(t, [][]byte{login}, frames)
+	assert.Equal(t, login[:4], rest)
+
+	command, err := protocol.EncodeCommand("WHERE#", 77)
+	require.NoError(t, err)
+	assert.Equal(t, uint16(77), concox.GetPackageSn(command))
+
+	_, err = Parse(generics.ParseRequest{Data: login, DeviceType: generics.DEVICE_TYPE_COOBAN})
+	assert.ErrorIs(t, err, ErrUnsupportedDevice)
+	_, err = Parse(generics.ParseRequest{Data: []byte{0x01}})
+	assert.ErrorIs(t, err, ErrUnsupportedDevice)
+}
+
+func TestParseJT808(t *testing.T) {
+	heartBeat, err := jt808.Encode(jt808.MessageHeartBeat, "13912345678", 7, nil)
+	require.NoError(t, err)
+
+	deviceType, _, ok := Detect(heartBeat)
+	require.True(t, ok)
+	assert.Equal(t, generics.DEVICE_TYPE_JT808, deviceType)
+
+	result, err := Parse(generics.ParseRequest{Data: heartBeat})
+	require.NoError(t, err)
+	assert.Equal(t, "013912345678", result.(jt808.Message).Header.Phone)
+
+	ack, err := BuildAck(generics.ParseRequest{Data: heartBeat, DeviceType: generics.DEVICE_TYPE_JT808})
+	require.NoError(t, err)
+	response, err := jt808.Decode(ack)
+	require.NoError(t, err)
+	assert.Equal(t, uint16(jt808.MessagePlatformGeneralResponse), response.Header.MessageID)
+	assert.Equal(t, []byte{0x00, 0x07, 0x00, 0x02, jt808.ResultSuccess}, response.Body)
+
+	for _, messageID := range []uint16{jt808.MessageRegistration, jt808.MessageAuthentication} {
+		packet, err := jt808.Encode(messageID, "13912345678", 8, []byte("13912345678"))
+		require.NoError(t, err)
+		_, err = BuildAck(generics.ParseRequest{Data: packet, DeviceType: generics.DEVICE_TYPE_JT808})
+		assert.ErrorIs(t, err, ErrAuthentication)
+	}
+
+	protocol, ok := Lookup(generics.DEVICE_TYPE_JT808)
+	require.True(t, ok)
+	_, err = protocol.EncodeCommand("WHERE#", 1)
+	assert.ErrorIs(t, err, ErrUnsupportedCommand)
+}
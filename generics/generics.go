@@ -6,6 +6,9 @@ import (
 	"gopkg.in/guregu/null.v4"
 )
 
+// DateFormat is the layout of the Date fields.
+const DateFormat = "2006-01-02 15:04:05"
+
 type Online struct {
 	Id         uint64 `json:"id"`
 	IP         string `json:"ip"`
@@ -122,6 +125,21 @@ type LbsLocation struct {
 	CreatedAt    string `json:"created_at"`
 }
 
+type WifiAccessPoint struct {
+	BSSID string `json:"bssid"`
+	RSSI  int    `json:"rssi"`
+}
+
+type WifiLocation struct {
+	DeviceId     uint64            `json:"device_id"`
+	Lbs          LbsLocation       `json:"lbs"`
+	AccessPoints []WifiAccessPoint `json:"access_points"`
+	SerialNumber string            `json:"serial_number"`
+	Date         string            `json:"date"`
+	Timestamp    time.Time         `json:"timestamp"`
+	CreatedAt    string            `json:"created_at"`
+}
+
 type Alarm struct {
 	DeviceID            uint64    `json:"deviceId" db:"column:deviceId" bson:"deviceId"`
 	Latitude            string    `json:"latitude" db:"column:latitude" bson:"latitude"`
@@ -140,6 +158,7 @@ type Alarm struct {
 	Language            string    `json:"language" db:"column:language" bson:"language"`
 	Date                string    `json:"date" db:"column:date" bson:"date"`
 	SerialNumber        string    `json:"serialNumber" db:"column:serialNumber" bson:"serialNumber"`
+	FenceNumber         int       `json:"fenceNumber,omitempty" db:"column:fenceNumber" bson:"fenceNumber,omitempty"`
 	CreatedAt           string    `json:"created_at" db:"column:created_at" bson:"created_at"`
 	UpdatedAt           string    `json:"updated_at" db:"column:updated_at" bson:"updated_at"`
 	Timestamp           time.Time `json:"timestamp" bson:"timestamp" `
@@ -155,9 +174,10 @@ type ParseResponse struct {
 }
 
 type ParseRequest struct {
-	Data   []byte `json:"data,omitempty"`
-	Imei   string `json:"imei,omitempty"`
-	Device Device `json:"device,omitempty"`
+	Data       []byte     `json:"data,omitempty"`
+	Imei       string     `json:"imei,omitempty"`
+	Device     Device     `json:"device,omitempty"`
+	DeviceType DeviceType `json:"device_type,omitempty"`
 }
 
 type DeviceType int32
@@ -170,4 +190,5 @@ const (
 	DEVICE_TYPE_COOBAN
 	DEVICE_TYPE_Q_BIT
 	DEVICE_TYPE_V_TRACK
+	DEVICE_TYPE_JT808
 )
@@ -0,0 +1,134 @@
+package generics
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+)
+
+const (
+	ImeiLength    = 15
+	ImeiBCDLength = 8
+)
+
+var (
+	// ErrImeiFormat occurs when an IMEI is not 15 digits.
+	ErrImeiFormat = errors.New("imei: must be 15 digits")
+	// ErrImeiCheckDigit occurs when the last digit of an IMEI fails the Luhn check.
+	ErrImeiCheckDigit = errors.New("imei: invalid check digit")
+	// ErrImeiBCD occurs when a BCD IMEI is not 8 bytes, has a non-zero first nibble or a nibble above 9.
+	ErrImeiBCD = errors.New("imei: invalid BCD encoding")
+)
+
+// Imei is a 15 digit IMEI. The zero value is the empty IMEI and marshals as an empty string. Only
+// ParseIMEI verifies the check digit: devices with a wrong one exist, so DecodeIMEIBCD, ParseIMEIDigits and
+// unmarshalling keep their IMEIs.
+type Imei string
+
+// ParseIMEI validates the format and the Luhn check digit of an IMEI.
+func ParseIMEI(value string) (Imei, error) {
+	imei, err := ParseIMEIDigits(value)
+	if err != nil {
+		return "", err
+	}
+	if !LuhnValid(value) {
+		return "", ErrImeiCheckDigit
+	}
+	return imei, nil
+}
+
+// ParseIMEIDigits validates only the format of an IMEI, for IMEIs that may fail the Luhn check.
+func ParseIMEIDigits(value string) (Imei, error) {
+	if len(value) != ImeiLength || strings.Trim(value, "0123456789") != "" {
+		return "", ErrImeiFormat
+	}
+	return Imei(value), nil
+}
+
+// DecodeIMEIBCD decodes the 8 byte BCD IMEI of a login packet: a zero nibble followed by the 15 digits.
+// When only the check digit is wrong the digits are returned along with ErrImeiCheckDigit, so the caller
+// can decide whether to accept the device.
+func DecodeIMEIBCD(input []byte) (Imei, error) {
+	if len(input) != ImeiBCDLength {
+		return "", ErrImeiBCD
+	}
+
+	digits := hex.EncodeToString(input)
+	if digits[0] != '0' || strings.Trim(digits, "0123456789") != "" {
+		return "", ErrImeiBCD
+	}
+	if !LuhnValid(digits[1:]) {
+		return Imei(digits[1:]), ErrImeiCheckDigit
+	}
+	return Imei(digits[1:]), nil
+}
+
+// LuhnValid reports whether the last digit of digits is its Luhn check digit.
+func LuhnValid(digits string) bool {
+	sum := 0
+	for i := len(digits) - 1; i >= 0; i-- {
+		digit := int(digits[i] - '0')
+		if (len(digits)-i)%2 == 0 {
+			if digit *= 2; digit > 9 {
+				digit -= 9
+			}
+		}
+		sum += digit
+	}
+	return sum%10 == 0
+}
+
+func (i Imei) String() string {
+	return string(i)
+}
+
+// BCD encodes the IMEI in the 8 byte login packet layout. The check digit is not verified.
+func (i Imei) BCD() ([]byte, error) {
+	if _, err := ParseIMEIDigits(string(i)); err != nil {
+		return nil, err
+	}
+	return hex.DecodeString("0" + string(i))
+}
+
+func (i Imei) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(i))
+}
+
+func (i *Imei) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	return i.set(value)
+}
+
+func (i Imei) MarshalBSONValue() (bsontype.Type, []byte, error) {
+	return bson.MarshalValue(string(i))
+}
+
+func (i *Imei) UnmarshalBSONValue(t bsontype.Type, data []byte) error {
+	var value string
+	if err := (bson.RawValue{Type: t, Value: data}).Unmarshal(&value); err != nil {
+		return err
+	}
+	return i.set(value)
+}
+
+func (i *Imei) set(value string) error {
+	if value == "" {
+		*i = ""
+		return nil
+	}
+
+	imei, err := ParseIMEIDigits(value)
+	if err != nil {
+		return fmt.Errorf("%w: %q", err, value)
+	}
+	*i = imei
+	return nil
+}
@@ -0,0 +1,61 @@
+package generics
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestImei(t *testing.T) {
+	imei, err := ParseIMEI("490154203237518")
+	require.NoError(t, err)
+	bcd, err := imei.BCD()
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0x04, 0x90, 0x15, 0x42, 0x03, 0x23, 0x75, 0x18}, bcd)
+
+	decoded, err := DecodeIMEIBCD(bcd)
+	require.NoError(t, err)
+	assert.Equal(t, imei, decoded)
+
+	decoded, err = DecodeIMEIBCD([]byte{0x04, 0x90, 0x15, 0x42, 0x03, 0x23, 0x75, 0x19})
+	assert.ErrorIs(t, err, ErrImeiCheckDigit)
+	assert.Equal(t, Imei("490154203237519"), decoded)
+
+	_, err = Imei("12345").BCD()
+	assert.ErrorIs(t, err, ErrImeiFormat)
+
+	_, err = ParseIMEI("490154203237519")
+	assert.ErrorIs(t, err, ErrImeiCheckDigit)
+	_, err = ParseIMEI("49015420323751")
+	assert.ErrorIs(t, err, ErrImeiFormat)
+	digits, err := ParseIMEIDigits("490154203237519")
+	require.NoError(t, err)
+	assert.Equal(t, Imei("490154203237519"), digits)
+	_, err = DecodeIMEIBCD([]byte{0x14, 0x90, 0x15, 0x42, 0x03, 0x23, 0x75, 0x18})
+	assert.ErrorIs(t, err, ErrImeiBCD)
+	_, err = DecodeIMEIBCD([]byte{0x04, 0x90, 0x15, 0x42, 0x03, 0x23, 0x75, 0x1f})
+	assert.ErrorIs(t, err, ErrImeiBCD)
+
+	type device struct {
+		Imei Imei `json:"imei" bson:"imei"`
+	}
+
+	data, err := json.Marshal(device{Imei: imei})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"imei":"490154203237518"}`, string(data))
+	var fromJSON device
+	require.NoError(t, json.Unmarshal(data, &fromJSON))
+	assert.Equal(t, imei, fromJSON.Imei)
+	require.NoError(t, json.Unmarshal([]byte(`{"imei":"490154203237519"}`), &fromJSON))
+	assert.Equal(t, Imei("490154203237519"), fromJSON.Imei)
+	assert.ErrorIs(t, json.Unmarshal([]byte(`{"imei":"49015420323751"}`), &fromJSON), ErrImeiFormat)
+
+	data, err = bson.Marshal(device{Imei: imei})
+	require.NoError(t, err)
+	var fromBSON device
+	require.NoError(t, bson.Unmarshal(data, &fromBSON))
+	assert.Equal(t, imei, fromBSON.Imei)
+}
@@ -13,12 +13,13 @@ import (
 
 	"github.com/natefinch/lumberjack"
 	"github.com/xen0tic/utils/devices/concox"
+	"github.com/xen0tic/utils/generics"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
 
 const (
-	ConstDefaultDateFormat = "2006-01-02 15:04:05"
+	ConstDefaultDateFormat = generics.DateFormat
 	RedisDevices           = "devices"
 	RedisLocations         = "locations"
 	RedisOnlines           = "onlines"
@@ -235,49 +236,25 @@ func GetRemoteAddr(remoteAddr string) string {
 	return strings.ReplaceAll(strings.ReplaceAll(remoteAddr, ":", ""), ".", "")
 }
 
+// GetPackageSn returns the serial number of a packet as four hex digits, or an empty string when the
+// packet is too short. It reads the same bytes as concox.GetPackageSn.
 func GetPackageSn(input []byte) string {
-	arr := ConvertByteToHex(input)
-	result := ""
-	tmp := arr[12:14]
-	for _, item := range tmp {
-		result += item
+	if len(input) < 10 {
+		return ""
 	}
-	return result
+	return fmt.Sprintf("%04x", concox.GetPackageSn(input))
 }
 
+// GetPackageSnInNumber returns the serial number of a packet, or 0 when the packet is too short.
 func GetPackageSnInNumber(input []byte) int {
-	val, _ := strconv.Atoi(GetPackageSn(input))
-	return val
+	return int(concox.GetPackageSn(input))
 }
 
 func SplitPackage(input []byte, array [][]byte) [][]byte {
-	
-	if len(input) < 10 {
-		return array
-	}
-	
-	if !concox.IsNormalPackage(input) && !concox.IsLongPackage(input) {
-		return array
-	}
-	
-	iLen := int(input[2])
-	if concox.IsLongPackage(input) {
-		fd, _ := strconv.ParseInt(ConvertByteToString(input[2:4]), 16, 64)
-		iLen = int(fd) + 1
-	}
-	
-	iLen += 5
-	
-	if len(input) == iLen {
-		array = append(array, input)
-	} else if len(input) > iLen {
-		if concox.ValidatePackage(input[:iLen]) {
-			array = append(array, input[:iLen])
-		}
-		return SplitPackage(input[iLen:], array)
-	}
-	
-	return array
+	framer := concox.NewFramer()
+	frames, _ := framer.Feed(input)
+	rest, _ := framer.Flush()
+	return append(append(array, frames...), rest...)
 }
 
 func SortArray(array [][]byte) [][]byte {
@@ -292,14 +269,22 @@ func SortArray(array [][]byte) [][]byte {
 }
 
 func DecodeLocation(latitude float64) float64 {
-	return ToFixed(latitude/60.0/30000.0, 6)
+	return concox.DecodeCoordinate(latitude)
+}
+
+func DecodeSignedLocation(latitude, longitude uint32, courseStatus uint16) (float64, float64) {
+	return concox.DecodeCourseStatus(courseStatus).Coordinates(latitude, longitude)
 }
 
+// GetDeviceImei returns the IMEI of a login packet, including one that fails its Luhn check, or an empty
+// string when the packet is too short or the IMEI is not BCD.
 func GetDeviceImei(input []byte) string {
-	result := ""
-	tmp := input[4:12]
-	for _, item := range tmp {
-		result = result + fmt.Sprintf("%02x", int(item))
+	if len(input) < 12 {
+		return ""
+	}
+	imei, err := generics.DecodeIMEIBCD(input[4:12])
+	if err != nil && !errors.Is(err, generics.ErrImeiCheckDigit) {
+		return ""
 	}
-	return LeftPad2Len(result[1:], "0", 15)
+	return imei.String()
 }